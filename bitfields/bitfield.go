@@ -1,6 +1,9 @@
 package bitfields
 
-import "fmt"
+import (
+	"fmt"
+	"math/bits"
+)
 
 // Note: bitfield indices and lengths are generally all uint32, as this is used in SSZ for lengths too.
 
@@ -101,3 +104,77 @@ func Covers(af []byte, bf []byte) (bool, error) {
 	}
 	return true, nil
 }
+
+func checkSameByteLen(af []byte, bf []byte) error {
+	if a, b := len(af), len(bf); a != b {
+		return fmt.Errorf("bitfield byte-length mismatch: %d <> %d", a, b)
+	}
+	return nil
+}
+
+// Returns a new bitfield with the bits set to 1 that are set in af or bf.
+// For bitlists of the same bitlength, the delimiting bit is preserved.
+func Or(af []byte, bf []byte) ([]byte, error) {
+	if err := checkSameByteLen(af, bf); err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(af), len(af))
+	for i := 0; i < len(af); i++ {
+		out[i] = af[i] | bf[i]
+	}
+	return out, nil
+}
+
+// Returns a new bitfield with the bits set to 1 that are set in both af and bf.
+// For bitlists of the same bitlength, the delimiting bit is preserved.
+func And(af []byte, bf []byte) ([]byte, error) {
+	if err := checkSameByteLen(af, bf); err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(af), len(af))
+	for i := 0; i < len(af); i++ {
+		out[i] = af[i] & bf[i]
+	}
+	return out, nil
+}
+
+// Returns a new bitfield with the bits set to 1 that are set in either af or bf, but not both.
+// This clears the delimiting bit of bitlists, see BitlistXor for bitlists.
+func Xor(af []byte, bf []byte) ([]byte, error) {
+	if err := checkSameByteLen(af, bf); err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(af), len(af))
+	for i := 0; i < len(af); i++ {
+		out[i] = af[i] ^ bf[i]
+	}
+	return out, nil
+}
+
+// Returns true if there is any bit set to 1 in both af and bf.
+// This does not ignore the delimiting bit of bitlists, see BitlistOverlaps for bitlists.
+func Overlaps(af []byte, bf []byte) (bool, error) {
+	if err := checkSameByteLen(af, bf); err != nil {
+		return false, err
+	}
+	for i := 0; i < len(af); i++ {
+		if af[i]&bf[i] != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns the indices of the bits set to 1, in increasing order.
+// This does not ignore the delimiting bit of bitlists, see BitlistOnesIndices for bitlists.
+func OnesIndices(v []byte) []uint64 {
+	out := make([]uint64, 0, BitvectorOnesCount(v))
+	for i, b := range v {
+		for b != 0 {
+			j := bits.TrailingZeros8(b)
+			out = append(out, uint64(i)<<3|uint64(j))
+			b &= b - 1
+		}
+	}
+	return out
+}
@@ -1,6 +1,7 @@
 package bitfields
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 )
@@ -218,3 +219,69 @@ func TestCovers(t *testing.T) {
 		})
 	}
 }
+
+func TestBitwiseOps(t *testing.T) {
+	cases := []struct {
+		a        []byte
+		b        []byte
+		or       []byte
+		and      []byte
+		xor      []byte
+		overlaps bool
+	}{
+		{[]byte{}, []byte{}, []byte{}, []byte{}, []byte{}, false},
+		{[]byte{0}, []byte{0}, []byte{0}, []byte{0}, []byte{0}, false},
+		{[]byte{0xf0}, []byte{0x0f}, []byte{0xff}, []byte{0}, []byte{0xff}, false},
+		{[]byte{0xf1}, []byte{0x1f}, []byte{0xff}, []byte{0x11}, []byte{0xee}, true},
+		{[]byte{0xff, 0x01}, []byte{0x00, 0x03}, []byte{0xff, 0x03}, []byte{0x00, 0x01}, []byte{0xff, 0x02}, true},
+	}
+	for _, testCase := range cases {
+		t.Run(fmt.Sprintf("%b <> %b", testCase.a, testCase.b), func(t *testing.T) {
+			for _, op := range []struct {
+				name     string
+				fn       func(a []byte, b []byte) ([]byte, error)
+				expected []byte
+			}{
+				{"or", Or, testCase.or},
+				{"and", And, testCase.and},
+				{"xor", Xor, testCase.xor},
+			} {
+				res, err := op.fn(testCase.a, testCase.b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(res, op.expected) {
+					t.Errorf("%s: expected %b but got %b", op.name, op.expected, res)
+				}
+			}
+			if res, err := Overlaps(testCase.a, testCase.b); err != nil {
+				t.Fatal(err)
+			} else if res != testCase.overlaps {
+				t.Errorf("expected overlaps %v", testCase.overlaps)
+			}
+		})
+	}
+	if _, err := Or([]byte{0}, []byte{0, 0}); err == nil {
+		t.Error("expected byte-length mismatch error")
+	}
+}
+
+func TestOnesIndices(t *testing.T) {
+	cases := []struct {
+		v       []byte
+		indices []uint64
+	}{
+		{[]byte{}, []uint64{}},
+		{[]byte{0}, []uint64{}},
+		{[]byte{1}, []uint64{0}},
+		{[]byte{0x81, 0, 0x12}, []uint64{0, 7, 17, 20}},
+	}
+	for _, testCase := range cases {
+		t.Run(fmt.Sprintf("v %b", testCase.v), func(t *testing.T) {
+			res := OnesIndices(testCase.v)
+			if fmt.Sprint(res) != fmt.Sprint(testCase.indices) {
+				t.Errorf("expected indices %v but got %v", testCase.indices, res)
+			}
+		})
+	}
+}
@@ -74,3 +74,56 @@ func BitlistOnesCount(v []byte) uint64 {
 	count += uint64(bits.OnesCount8(last))
 	return count
 }
+
+// Returns a new bitlist with the bits set to 1 that are set in either af or bf, but not both.
+// The bitlists must have the same bitlength, the delimiting bit is preserved.
+func BitlistXor(af []byte, bf []byte) ([]byte, error) {
+	if a, b := BitlistLen(af), BitlistLen(bf); a != b {
+		return nil, fmt.Errorf("bitlist length mismatch: %d <> %d", a, b)
+	}
+	out, err := Xor(af, bf)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > 0 {
+		SetBit(out, BitlistLen(af), true)
+	}
+	return out, nil
+}
+
+// Returns true if there is any bit set to 1 in both af and bf, excluding the delimiter bit.
+// The bitlists must have the same bitlength.
+func BitlistOverlaps(af []byte, bf []byte) (bool, error) {
+	if a, b := BitlistLen(af), BitlistLen(bf); a != b {
+		return false, fmt.Errorf("bitlist length mismatch: %d <> %d", a, b)
+	}
+	if err := checkSameByteLen(af, bf); err != nil {
+		return false, err
+	}
+	if len(af) == 0 {
+		return false, nil
+	}
+	end := len(af) - 1
+	for i := 0; i < end; i++ {
+		if af[i]&bf[i] != 0 {
+			return true, nil
+		}
+	}
+	last := af[end] & bf[end]
+	if last == 0 {
+		return false, nil
+	}
+	// ignore the delimiter bit.
+	last ^= uint8(1) << BitIndex(last)
+	return last != 0, nil
+}
+
+// Returns the indices of the bits set to 1, in increasing order, excluding the delimiter bit.
+func BitlistOnesIndices(v []byte) []uint64 {
+	out := OnesIndices(v)
+	if len(out) > 0 && len(v) > 0 && v[len(v)-1] != 0 {
+		// the delimiter is the last set bit
+		out = out[:len(out)-1]
+	}
+	return out
+}
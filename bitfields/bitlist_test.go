@@ -53,3 +53,41 @@ func TestBitlistLen(t *testing.T) {
 		})
 	}
 }
+
+func TestBitlistBitwiseOps(t *testing.T) {
+	cases := []struct {
+		a        []byte
+		b        []byte
+		xor      []byte
+		overlaps bool
+		ones     []uint64
+	}{
+		{[]byte{1}, []byte{1}, []byte{1}, false, []uint64{}},
+		{[]byte{0x1a}, []byte{0x12}, []byte{0x18}, true, []uint64{1, 3}},
+		{[]byte{0x1a}, []byte{0x15}, []byte{0x1f}, false, []uint64{1, 3}},
+		{[]byte{0xff, 0x02}, []byte{0x00, 0x03}, []byte{0xff, 0x03}, false, []uint64{0, 1, 2, 3, 4, 5, 6, 7}},
+		{[]byte{0x01, 0x03}, []byte{0x01, 0x03}, []byte{0x00, 0x02}, true, []uint64{0, 8}},
+	}
+	for _, testCase := range cases {
+		t.Run(fmt.Sprintf("%b <> %b", testCase.a, testCase.b), func(t *testing.T) {
+			res, err := BitlistXor(testCase.a, testCase.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(res) != fmt.Sprint(testCase.xor) {
+				t.Errorf("expected xor %b but got %b", testCase.xor, res)
+			}
+			if res, err := BitlistOverlaps(testCase.a, testCase.b); err != nil {
+				t.Fatal(err)
+			} else if res != testCase.overlaps {
+				t.Errorf("expected overlaps %v", testCase.overlaps)
+			}
+			if res := BitlistOnesIndices(testCase.a); fmt.Sprint(res) != fmt.Sprint(testCase.ones) {
+				t.Errorf("expected indices %v but got %v", testCase.ones, res)
+			}
+		})
+	}
+	if _, err := BitlistXor([]byte{0x02}, []byte{0x04}); err == nil {
+		t.Error("expected bitlist length mismatch error")
+	}
+}
@@ -0,0 +1,131 @@
+package typed
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+)
+
+// Bitlist is a list of bits, up to a limit, backed by the packed bits, including the delimiter bit.
+// The zero value is not usable, see NewBitlist and BitlistFromBytes.
+type Bitlist struct {
+	bits  []byte
+	limit uint64
+}
+
+// NewBitlist creates a bitlist of the given length, with all bits set to 0.
+func NewBitlist(length uint64, limit uint64) (*Bitlist, error) {
+	if length > limit {
+		return nil, fmt.Errorf("bitlist length %d exceeds limit %d", length, limit)
+	}
+	bits := make([]byte, (length>>3)+1)
+	bitfields.SetBit(bits, length, true)
+	return &Bitlist{bits: bits, limit: limit}, nil
+}
+
+// BitlistFromBytes creates a bitlist from a copy of the packed bits, including the delimiter bit.
+func BitlistFromBytes(b []byte, limit uint64) (*Bitlist, error) {
+	if err := bitfields.BitlistCheck(b, limit); err != nil {
+		return nil, err
+	}
+	return &Bitlist{bits: append([]byte(nil), b...), limit: limit}, nil
+}
+
+// Bytes returns the packed bits, including the delimiter bit. The result must not be modified.
+func (b *Bitlist) Bytes() []byte {
+	return b.bits
+}
+
+// Limit returns the maximum number of bits.
+func (b *Bitlist) Limit() uint64 {
+	return b.limit
+}
+
+func (b *Bitlist) BitLen() uint64 {
+	return bitfields.BitlistLen(b.bits)
+}
+
+// Get returns bit i. Bits out of range are false.
+func (b *Bitlist) Get(i uint64) bool {
+	if i >= b.BitLen() {
+		return false
+	}
+	return bitfields.GetBit(b.bits, i)
+}
+
+// Set changes bit i. It panics if i is out of range, use Append or Resize to grow the bitlist first.
+func (b *Bitlist) Set(i uint64, v bool) {
+	if n := b.BitLen(); i >= n {
+		panic(fmt.Errorf("bit index %d out of range, bitlist length is %d", i, n))
+	}
+	bitfields.SetBit(b.bits, i, v)
+}
+
+func (b *Bitlist) Check() error {
+	return bitfields.BitlistCheck(b.bits, b.limit)
+}
+
+// Append adds a bit to the end of the bitlist.
+func (b *Bitlist) Append(v bool) error {
+	n := b.BitLen()
+	if n >= b.limit {
+		return fmt.Errorf("bitlist is full, limit is %d", b.limit)
+	}
+	// move the delimiter bit up by one
+	bitfields.SetBit(b.bits, n, v)
+	if (n+1)&7 == 0 {
+		b.bits = append(b.bits, 1)
+	} else {
+		bitfields.SetBit(b.bits, n+1, true)
+	}
+	return nil
+}
+
+// Resize changes the length of the bitlist. New bits are 0, bits beyond the new length are dropped.
+func (b *Bitlist) Resize(length uint64) error {
+	if length > b.limit {
+		return fmt.Errorf("bitlist length %d exceeds limit %d", length, b.limit)
+	}
+	n := b.BitLen()
+	out := make([]byte, (length>>3)+1)
+	copy(out, b.bits)
+	if length < n {
+		out[length>>3] &= (1 << (length & 7)) - 1
+	} else {
+		// clear the old delimiter bit
+		bitfields.SetBit(out, n, false)
+	}
+	bitfields.SetBit(out, length, true)
+	b.bits = out
+	return nil
+}
+
+// OnesCount counts the bits set to 1.
+func (b *Bitlist) OnesCount() uint64 {
+	return bitfields.BitlistOnesCount(b.bits)
+}
+
+func (b *Bitlist) Copy() *Bitlist {
+	return &Bitlist{bits: append([]byte(nil), b.bits...), limit: b.limit}
+}
+
+func (b *Bitlist) Deserialize(dr *codec.DecodingReader) error {
+	return dr.BitList(&b.bits, b.limit)
+}
+
+func (b *Bitlist) Serialize(w *codec.EncodingWriter) error {
+	return w.BitList(b.bits)
+}
+
+func (b *Bitlist) ByteLength() uint64 {
+	return uint64(len(b.bits))
+}
+
+func (b *Bitlist) FixedLength() uint64 {
+	return 0
+}
+
+func (b *Bitlist) HashTreeRoot(hFn tree.HashFn) tree.Root {
+	return hFn.BitListHTR(b.bits, b.limit)
+}
@@ -0,0 +1,92 @@
+package typed
+
+import (
+	"bytes"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestBitlistType(t *testing.T) {
+	var _ bitfields.Bitfield = (*Bitlist)(nil)
+	var _ bitfields.CheckedBitfield = (*Bitlist)(nil)
+	var _ bitfields.SizedBits = (*Bitlist)(nil)
+	var _ codec.Serializable = (*Bitlist)(nil)
+	var _ codec.Deserializable = (*Bitlist)(nil)
+	var _ tree.HTR = (*Bitlist)(nil)
+
+	b, err := NewBitlist(0, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected []bool
+	for i := 0; i < 20; i++ {
+		v := i%3 == 0
+		if err := b.Append(v); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, v)
+		if err := b.Check(); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+	if err := b.Append(true); err == nil {
+		t.Fatal("expected bitlist to be full")
+	}
+	if b.BitLen() != 20 || b.OnesCount() != 7 {
+		t.Fatalf("unexpected length %d or ones count %d", b.BitLen(), b.OnesCount())
+	}
+	for i, v := range expected {
+		if b.Get(uint64(i)) != v {
+			t.Fatalf("bit %d: expected %v", i, v)
+		}
+	}
+	b.Set(1, true)
+	if !b.Get(1) {
+		t.Fatal("expected bit 1 to be set")
+	}
+	b.Set(1, false)
+
+	// shrink, then grow again: the dropped bits must be cleared
+	if err := b.Resize(4); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), []byte{0x19}) {
+		t.Fatalf("unexpected bits after shrinking: %x", b.Bytes())
+	}
+	if err := b.Resize(17); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), []byte{0x09, 0x00, 0x02}) {
+		t.Fatalf("unexpected bits after growing: %x", b.Bytes())
+	}
+	if err := b.Resize(21); err == nil {
+		t.Fatal("expected limit error")
+	}
+
+	enc, err := codec.EncodeToBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := NewBitlist(0, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Deserialize(codec.NewBytesDecodingReader(enc)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Bytes(), b.Bytes()) {
+		t.Fatalf("decoded %x does not match %x", decoded.Bytes(), b.Bytes())
+	}
+	hFn := tree.GetHashFn()
+	if decoded.HashTreeRoot(hFn) != hFn.BitListHTR(b.Bytes(), 20) {
+		t.Fatal("unexpected hash tree root")
+	}
+	if err := decoded.Deserialize(codec.NewBytesDecodingReader([]byte{0, 0, 0, 1})); err == nil {
+		t.Fatal("expected decoding error for too many bits")
+	}
+	if _, err := BitlistFromBytes([]byte{0x01, 0x00}, 20); err == nil {
+		t.Fatal("expected error for missing delimiter bit")
+	}
+}
@@ -0,0 +1,86 @@
+package typed
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+)
+
+// Bitvector is a fixed-length sequence of bits, backed by the packed bits.
+// The zero value is a bitvector of length 0, see NewBitvector and BitvectorFromBytes.
+type Bitvector struct {
+	bits   []byte
+	length uint64
+}
+
+// NewBitvector creates a bitvector of the given length, with all bits set to 0.
+func NewBitvector(length uint64) *Bitvector {
+	return &Bitvector{bits: make([]byte, (length+7)>>3), length: length}
+}
+
+// BitvectorFromBytes creates a bitvector from a copy of the packed bits.
+func BitvectorFromBytes(b []byte, length uint64) (*Bitvector, error) {
+	if err := bitfields.BitvectorCheck(b, length); err != nil {
+		return nil, err
+	}
+	return &Bitvector{bits: append([]byte(nil), b...), length: length}, nil
+}
+
+// Bytes returns the packed bits. The result must not be modified.
+func (b *Bitvector) Bytes() []byte {
+	return b.bits
+}
+
+func (b *Bitvector) BitLen() uint64 {
+	return b.length
+}
+
+// Get returns bit i. Bits out of range are false.
+func (b *Bitvector) Get(i uint64) bool {
+	if i >= b.length {
+		return false
+	}
+	return bitfields.GetBit(b.bits, i)
+}
+
+// Set changes bit i. It panics if i is out of range.
+func (b *Bitvector) Set(i uint64, v bool) {
+	if i >= b.length {
+		panic(fmt.Errorf("bit index %d out of range, bitvector length is %d", i, b.length))
+	}
+	bitfields.SetBit(b.bits, i, v)
+}
+
+func (b *Bitvector) Check() error {
+	return bitfields.BitvectorCheck(b.bits, b.length)
+}
+
+// OnesCount counts the bits set to 1.
+func (b *Bitvector) OnesCount() uint64 {
+	return bitfields.BitvectorOnesCount(b.bits)
+}
+
+func (b *Bitvector) Copy() *Bitvector {
+	return &Bitvector{bits: append([]byte(nil), b.bits...), length: b.length}
+}
+
+func (b *Bitvector) Deserialize(dr *codec.DecodingReader) error {
+	return dr.BitVector(&b.bits, b.length)
+}
+
+func (b *Bitvector) Serialize(w *codec.EncodingWriter) error {
+	return w.BitVector(b.bits)
+}
+
+func (b *Bitvector) ByteLength() uint64 {
+	return (b.length + 7) >> 3
+}
+
+func (b *Bitvector) FixedLength() uint64 {
+	return (b.length + 7) >> 3
+}
+
+func (b *Bitvector) HashTreeRoot(hFn tree.HashFn) tree.Root {
+	return hFn.BitVectorHTR(b.bits)
+}
@@ -0,0 +1,52 @@
+package typed
+
+import (
+	"bytes"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestBitvectorType(t *testing.T) {
+	var _ bitfields.Bitfield = (*Bitvector)(nil)
+	var _ bitfields.CheckedBitfield = (*Bitvector)(nil)
+	var _ bitfields.SizedBits = (*Bitvector)(nil)
+	var _ codec.Serializable = (*Bitvector)(nil)
+	var _ codec.Deserializable = (*Bitvector)(nil)
+	var _ tree.HTR = (*Bitvector)(nil)
+
+	b := NewBitvector(12)
+	b.Set(0, true)
+	b.Set(11, true)
+	if !b.Get(0) || b.Get(1) || !b.Get(11) || b.Get(12) {
+		t.Fatal("unexpected bits")
+	}
+	if b.OnesCount() != 2 || b.BitLen() != 12 {
+		t.Fatalf("unexpected ones count %d or length %d", b.OnesCount(), b.BitLen())
+	}
+	if err := b.Check(); err != nil {
+		t.Fatal(err)
+	}
+	enc, err := codec.EncodeToBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, []byte{0x01, 0x08}) {
+		t.Fatalf("unexpected encoding %x", enc)
+	}
+	decoded := NewBitvector(12)
+	if err := decoded.Deserialize(codec.NewBytesDecodingReader(enc)); err != nil {
+		t.Fatal(err)
+	}
+	hFn := tree.GetHashFn()
+	if decoded.HashTreeRoot(hFn) != b.HashTreeRoot(hFn) {
+		t.Fatal("decoded bitvector does not match")
+	}
+	if err := decoded.Deserialize(codec.NewBytesDecodingReader([]byte{0x01, 0x10})); err == nil {
+		t.Fatal("expected error for out of range bit")
+	}
+	if _, err := BitvectorFromBytes([]byte{0x01}, 12); err == nil {
+		t.Fatal("expected error for wrong byte length")
+	}
+}
@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BufferPool provides buffers to encode into, to avoid an allocation per encoding.
+type BufferPool interface {
+	// Get returns an empty buffer with at least the given capacity.
+	Get(size uint64) []byte
+	// Put gives a buffer back to the pool, when the caller is done with it.
+	Put(buf []byte)
+}
+
+// SyncBufferPool is a BufferPool backed by a sync.Pool. The zero value is ready to use.
+type SyncBufferPool struct {
+	pool sync.Pool
+}
+
+func (p *SyncBufferPool) Get(size uint64) []byte {
+	if v, ok := p.pool.Get().(*[]byte); ok && uint64(cap(*v)) >= size {
+		return (*v)[:0]
+	}
+	return make([]byte, 0, size)
+}
+
+func (p *SyncBufferPool) Put(buf []byte) {
+	p.pool.Put(&buf)
+}
+
+// EncodeInto encodes size bytes with fn, appending to buf[:0] if it has enough capacity,
+// or to a new buffer of exactly the size otherwise. It is an error if fn does not write exactly size bytes.
+func EncodeInto(buf []byte, size uint64, fn func(w *EncodingWriter) error) ([]byte, error) {
+	if uint64(cap(buf)) < size {
+		buf = make([]byte, 0, size)
+	}
+	ew := NewBytesEncodingWriter(buf[:0])
+	if err := fn(ew); err != nil {
+		return nil, err
+	}
+	out := ew.Bytes()
+	if uint64(len(out)) != size {
+		return nil, fmt.Errorf("encoded %d bytes, but expected %d bytes", len(out), size)
+	}
+	return out, nil
+}
+
+// EncodeToBytes encodes v to a new byte slice, allocated once with the byte length of v.
+func EncodeToBytes(v Serializable) ([]byte, error) {
+	return EncodeInto(nil, v.ByteLength(), v.Serialize)
+}
+
+// EncodeToPool encodes v to a buffer from the pool.
+// The caller can give the buffer back to the pool when done with it.
+func EncodeToPool(v Serializable, pool BufferPool) ([]byte, error) {
+	size := v.ByteLength()
+	buf := pool.Get(size)
+	out, err := EncodeInto(buf, size, v.Serialize)
+	if err != nil {
+		pool.Put(buf)
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,110 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// testBytes encodes as its contents, and claims the given size.
+type testBytes struct {
+	data []byte
+	size uint64
+}
+
+func (b *testBytes) Serialize(w *EncodingWriter) error {
+	return w.Write(b.data)
+}
+
+func (b *testBytes) ByteLength() uint64 {
+	return b.size
+}
+
+func (b *testBytes) FixedLength() uint64 {
+	return 0
+}
+
+// countingPool records the buffers that are given back.
+type countingPool struct {
+	SyncBufferPool
+	puts int
+}
+
+func (p *countingPool) Put(buf []byte) {
+	p.puts++
+	p.SyncBufferPool.Put(buf)
+}
+
+func TestEncodeInto(t *testing.T) {
+	v := &testBytes{data: []byte{1, 2, 3}, size: 3}
+	buf := make([]byte, 5, 8)
+	out, err := EncodeInto(buf, v.ByteLength(), v.Serialize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, v.data) {
+		t.Fatalf("unexpected output %x", out)
+	}
+	if &out[0] != &buf[0] {
+		t.Fatal("expected the buffer to be reused")
+	}
+	small := make([]byte, 0, 2)
+	out, err = EncodeInto(small, v.ByteLength(), v.Serialize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, v.data) || cap(out) != 3 {
+		t.Fatalf("unexpected output %x with capacity %d", out, cap(out))
+	}
+	if _, err := EncodeInto(nil, 4, v.Serialize); err == nil {
+		t.Fatal("expected size mismatch error")
+	}
+	out, err = EncodeToBytes(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, v.data) || cap(out) != 3 {
+		t.Fatalf("unexpected output %x with capacity %d", out, cap(out))
+	}
+}
+
+func TestEncodeToPool(t *testing.T) {
+	var pool countingPool
+	v := &testBytes{data: []byte{1, 2, 3}, size: 3}
+	out, err := EncodeToPool(v, &pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, v.data) {
+		t.Fatalf("unexpected output %x", out)
+	}
+	pool.Put(out)
+	// the buffer is given back on error
+	invalid := &testBytes{data: []byte{1, 2}, size: 3}
+	if _, err := EncodeToPool(invalid, &pool); err == nil {
+		t.Fatal("expected size mismatch error")
+	}
+	if pool.puts != 2 {
+		t.Fatalf("expected 2 buffers to be given back, got %d", pool.puts)
+	}
+	failing := errors.New("failing")
+	_, err = EncodeInto(nil, 3, func(w *EncodingWriter) error {
+		return failing
+	})
+	if !errors.Is(err, failing) {
+		t.Fatalf("expected encoding error, got %v", err)
+	}
+}
+
+func TestSyncBufferPool(t *testing.T) {
+	var pool SyncBufferPool
+	buf := pool.Get(10)
+	if len(buf) != 0 || cap(buf) < 10 {
+		t.Fatalf("unexpected buffer length %d and capacity %d", len(buf), cap(buf))
+	}
+	pool.Put(buf)
+	// a larger buffer than pooled ones is still large enough
+	if buf := pool.Get(100); len(buf) != 0 || cap(buf) < 100 {
+		t.Fatalf("unexpected buffer length %d and capacity %d", len(buf), cap(buf))
+	}
+}
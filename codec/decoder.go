@@ -22,27 +22,51 @@ type Decodable interface {
 }
 
 type DecodingReader struct {
-	input   io.Reader
-	i       uint64
-	max     uint64
+	// nil if the reader decodes from buf instead.
+	input io.Reader
+	// the scope, when decoding from memory. Bytes are read from buf[i:max].
+	buf []byte
+	i   uint64
+	max uint64
+	// absolute position in the input, shared between all scopes of the same input.
+	pos *uint64
+	// absolute position of the start of the scope, when decoding from memory.
+	base    uint64
 	scratch [32]byte
 }
 
 func NewDecodingReader(input io.Reader, scope uint64) *DecodingReader {
-	return &DecodingReader{input: io.LimitReader(input, int64(scope)), i: 0, max: scope}
+	return &DecodingReader{input: io.LimitReader(input, int64(scope)), i: 0, max: scope, pos: new(uint64)}
+}
+
+// NewBytesDecodingReader decodes directly from the given bytes, with the full slice as scope.
+// Reads are plain copies, and sub-scopes are sub-slices of the input, without nested readers.
+// The input must not be modified while decoding.
+func NewBytesDecodingReader(data []byte) *DecodingReader {
+	return &DecodingReader{buf: data, i: 0, max: uint64(len(data))}
 }
 
 // SubScope returns a scope of the SSZ reader. Re-uses same scratchpad.
+//
+// When decoding from memory, the bytes of the sub-scope are consumed from the parent scope immediately,
+// and UpdateIndexFromScoped does not have to be called.
 func (dr *DecodingReader) SubScope(count uint64) (*DecodingReader, error) {
-	// TODO: based on scope, read a buffer ahead of time.
 	if span := dr.Scope(); span < count {
-		return nil, fmt.Errorf("cannot create scoped decoding reader, scope of %d bytes is bigger than parent scope has available space %d", count, span)
+		return nil, dr.Errorf("cannot create scoped decoding reader, scope of %d bytes is bigger than parent scope has available space %d", count, span)
+	}
+	if dr.input == nil {
+		start := dr.i
+		dr.i += count
+		return &DecodingReader{buf: dr.buf[start:dr.i], i: 0, max: count, base: dr.base + start}, nil
 	}
-	// TODO: don't nest readers, instead just limit input reads ourselves
-	return &DecodingReader{input: io.LimitReader(dr.input, int64(count)), i: 0, max: count}, nil
+	return &DecodingReader{input: io.LimitReader(dr.input, int64(count)), i: 0, max: count, pos: dr.pos}, nil
 }
 
 func (dr *DecodingReader) UpdateIndexFromScoped(other *DecodingReader) {
+	if dr.input == nil {
+		// already consumed when the sub-scope was created
+		return
+	}
 	dr.i += other.i
 }
 
@@ -51,6 +75,52 @@ func (dr *DecodingReader) Index() uint64 {
 	return dr.i
 }
 
+// Position returns how far we have read in the input, counting from the start of the outermost scope.
+// Unlike Index, this is not reset in sub-scopes.
+func (dr *DecodingReader) Position() uint64 {
+	if dr.input == nil {
+		return dr.base + dr.i
+	}
+	if dr.pos == nil {
+		return dr.i
+	}
+	return *dr.pos
+}
+
+func (dr *DecodingReader) advance(n int) {
+	if dr.pos != nil {
+		*dr.pos += uint64(n)
+	}
+}
+
+// Errorf creates a DecodeError at the current position.
+func (dr *DecodingReader) Errorf(format string, a ...interface{}) error {
+	return &DecodeError{Offset: dr.Position(), Err: fmt.Errorf(format, a...)}
+}
+
+// WrapErr turns the error into a DecodeError at the current position, unless it already is one.
+func (dr *DecodingReader) WrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*DecodeError); ok {
+		return err
+	}
+	return &DecodeError{Offset: dr.Position(), Err: err}
+}
+
+// WrapField attributes the error to the container field with the given name.
+// If the error is not a DecodeError yet, it becomes one at the current position.
+func (dr *DecodingReader) WrapField(name string, err error) error {
+	return wrapPath(PathElem{Field: name}, dr.Position(), err)
+}
+
+// WrapIndex attributes the error to the element (or unnamed field) with the given index.
+// If the error is not a DecodeError yet, it becomes one at the current position.
+func (dr *DecodingReader) WrapIndex(i uint64, err error) error {
+	return wrapPath(PathElem{Index: i}, dr.Position(), err)
+}
+
 // How far we can read (max - i = remaining bytes to read without error).
 // Note: when a child element is not fixed length,
 // the parent should set the scope, so that the child can infer its size from it.
@@ -60,22 +130,22 @@ func (dr *DecodingReader) Max() uint64 {
 
 func (dr *DecodingReader) hasScope(x uint64) error {
 	if ^uint64(0)-dr.i < x {
-		return fmt.Errorf("overflow: x: %d, i: %d, max: %d", x, dr.i, dr.max)
+		return dr.Errorf("overflow: x: %d, i: %d, max: %d", x, dr.i, dr.max)
 	}
 	v := dr.i + x
 	if v > dr.max {
-		return fmt.Errorf("cannot read %d bytes, %d beyond scope", x, v-dr.max)
+		return dr.Errorf("cannot read %d bytes, %d beyond scope", x, v-dr.max)
 	}
 	return nil
 }
 
 func (dr *DecodingReader) checkedIndexUpdate(x uint64) (n int, err error) {
 	if ^uint64(0)-dr.i < x {
-		return 0, fmt.Errorf("overflow: x: %d, i: %d, max: %d", x, dr.i, dr.max)
+		return 0, dr.Errorf("overflow: x: %d, i: %d, max: %d", x, dr.i, dr.max)
 	}
 	v := dr.i + x
 	if v > dr.max {
-		return int(dr.i), fmt.Errorf("cannot read %d bytes, %d beyond scope", x, v-dr.max)
+		return int(dr.i), dr.Errorf("cannot read %d bytes, %d beyond scope", x, v-dr.max)
 	}
 	dr.i = v
 	return int(x), nil
@@ -85,12 +155,19 @@ func (dr *DecodingReader) Skip(count uint64) (int, error) {
 	if n, err := dr.checkedIndexUpdate(count); err != nil {
 		return n, err
 	}
+	if dr.input == nil {
+		return int(count), nil
+	}
 	switch r := dr.input.(type) {
 	case io.Seeker:
 		n, err := r.Seek(int64(count), io.SeekCurrent)
+		if err == nil {
+			dr.advance(int(count))
+		}
 		return int(n), err
 	default:
 		n, err := io.CopyN(ioutil.Discard, dr.input, int64(count))
+		dr.advance(int(n))
 		return int(n), err
 	}
 }
@@ -100,15 +177,20 @@ func (dr *DecodingReader) Read(p []byte) (int, error) {
 	if len(p) == 0 {
 		return 0, nil
 	}
+	start := dr.i
 	if n, err := dr.checkedIndexUpdate(uint64(len(p))); err != nil {
 		return n, err
 	}
+	if dr.input == nil {
+		return copy(p, dr.buf[start:dr.i]), nil
+	}
 	n := 0
 	for n < len(p) {
 		v, err := dr.input.Read(p[n:])
 		n += v
+		dr.advance(v)
 		if err != nil {
-			return n, err
+			return n, dr.WrapErr(err)
 		}
 	}
 	return n, nil
@@ -139,10 +221,53 @@ func (dr *DecodingReader) Scope() uint64 {
 	return dr.Max() - dr.Index()
 }
 
+// CheckConsumed checks that exactly n bytes were read since the start position, see Position.
+// Values that fill a scope use this to reject trailing bytes: the position is tracked in stream mode too, unlike the scope.
+func (dr *DecodingReader) CheckConsumed(start uint64, n uint64) error {
+	if consumed := dr.Position() - start; consumed != n {
+		if consumed < n {
+			return dr.Errorf("value has %d trailing bytes", n-consumed)
+		}
+		return dr.Errorf("value read %d bytes, but only %d were available", consumed, n)
+	}
+	return nil
+}
+
 func (dr *DecodingReader) ReadOffset() (uint32, error) {
 	return dr.ReadUint32()
 }
 
+// PeekOffset reads the next offset, without moving forward. Only supported when decoding from memory.
+func (dr *DecodingReader) PeekOffset() (uint32, error) {
+	if dr.input != nil {
+		return 0, dr.Errorf("cannot peek offset, not decoding from memory")
+	}
+	if err := dr.hasScope(OFFSET_SIZE); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(dr.buf[dr.i : dr.i+OFFSET_SIZE]), nil
+}
+
+// ReadBytes reads the next count bytes. When decoding from memory,
+// the result is a sub-slice of the input, without copying, and must not be modified.
+func (dr *DecodingReader) ReadBytes(count uint64) ([]byte, error) {
+	if dr.input == nil {
+		start := dr.i
+		if _, err := dr.checkedIndexUpdate(count); err != nil {
+			return nil, err
+		}
+		return dr.buf[start:dr.i], nil
+	}
+	if err := dr.hasScope(count); err != nil {
+		return nil, err
+	}
+	out := make([]byte, count, count)
+	if _, err := dr.Read(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Deserialize vector. If fixedElemSize == 0, the item is regarded as dynamic length
 func (dr *DecodingReader) Vector(item func(i uint64) Deserializable, fixedElemSize uint64, length uint64) error {
 	if fixedElemSize != 0 {
@@ -152,7 +277,7 @@ func (dr *DecodingReader) Vector(item func(i uint64) Deserializable, fixedElemSi
 				return err
 			}
 			if err := item(i).Deserialize(sub); err != nil {
-				return fmt.Errorf("failed to deserialize item %d: %v", i, err)
+				return dr.WrapIndex(i, err)
 			}
 		}
 		return nil
@@ -170,9 +295,9 @@ func (dr *DecodingReader) Vector(item func(i uint64) Deserializable, fixedElemSi
 		var prev uint64
 		for i, off := range offsets {
 			if prev > off {
-				return fmt.Errorf("offset %d is too low, previous was %d", off, prev)
+				return dr.Errorf("offset %d is too low, previous was %d", off, prev)
 			}
-			item := item(uint64(0))
+			item := item(uint64(i))
 			next := scope
 			if len(offsets) > i+1 {
 				next = offsets[i+1]
@@ -182,7 +307,7 @@ func (dr *DecodingReader) Vector(item func(i uint64) Deserializable, fixedElemSi
 				return err
 			}
 			if err := item.Deserialize(sub); err != nil {
-				return fmt.Errorf("failed to serialize item %d: %v", i, err)
+				return dr.WrapIndex(uint64(i), err)
 			}
 			prev = next
 		}
@@ -198,11 +323,11 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 	}
 	if fixedElemSize != 0 {
 		if scope%fixedElemSize != 0 {
-			return fmt.Errorf("scope %d is not a multiple of expected element size: %d", scope, fixedElemSize)
+			return dr.Errorf("scope %d is not a multiple of expected element size: %d", scope, fixedElemSize)
 		}
 		length := scope / fixedElemSize
 		if length > limit {
-			return fmt.Errorf("too many items in list: %d > %d", length, limit)
+			return dr.Errorf("too many items in list: %d > %d", length, limit)
 		}
 		for i := uint64(0); i < length; i++ {
 			item := add()
@@ -211,7 +336,7 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 				return err
 			}
 			if err := item.Deserialize(sub); err != nil {
-				return err
+				return dr.WrapIndex(i, err)
 			}
 		}
 		return nil
@@ -221,11 +346,11 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 			return err
 		}
 		if firstOffset%4 != 0 {
-			return fmt.Errorf("first offset of list is invalid, not a multiple of 4: %d", firstOffset)
+			return dr.Errorf("first offset of list is invalid, not a multiple of 4: %d", firstOffset)
 		}
 		length := uint64(firstOffset / 4)
 		if length > limit {
-			return fmt.Errorf("too many items in list: %d > %d", length, limit)
+			return dr.Errorf("too many items in list: %d > %d", length, limit)
 		}
 		// TODO could optimize this
 		offsets := make([]uint64, 0, length)
@@ -240,7 +365,7 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 		var prev uint64
 		for i, off := range offsets {
 			if prev > off {
-				return fmt.Errorf("offset %d is too low, previous was %d", off, prev)
+				return dr.Errorf("offset %d is too low, previous was %d", off, prev)
 			}
 			item := add()
 			next := scope
@@ -256,7 +381,7 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 				return err
 			}
 			if err := item.Deserialize(sub); err != nil {
-				return fmt.Errorf("failed to deserialize item %d: %v", i, err)
+				return dr.WrapIndex(uint64(i), err)
 			}
 			prev = off
 		}
@@ -266,7 +391,7 @@ func (dr *DecodingReader) List(add func() Deserializable, fixedElemSize uint64,
 
 func (dr *DecodingReader) BitVector(dst *[]byte, bitLength uint64) error {
 	if dst == nil {
-		return fmt.Errorf("bitvector destination is nil")
+		return dr.Errorf("bitvector destination is nil")
 	}
 	// grow the destination if necessary
 	byteLen := (bitLength + 7) >> 3
@@ -278,13 +403,17 @@ func (dr *DecodingReader) BitVector(dst *[]byte, bitLength uint64) error {
 	if _, err := dr.Read(*dst); err != nil {
 		return err
 	}
-	return bitfields.BitvectorCheck(*dst, bitLength)
+	if err := bitfields.BitvectorCheck(*dst, bitLength); err != nil {
+		return dr.Errorf("invalid bitvector: %w", err)
+	}
+	return nil
 }
 
 func (dr *DecodingReader) BitList(dst *[]byte, bitLimit uint64) error {
 	byteLen := dr.Scope()
-	if byteLimit := (bitLimit + 7) >> 3; byteLen > byteLimit {
-		return fmt.Errorf("bitlist is too big: %d bytes, limit is %d (bitlimit %d)", byteLen, byteLimit, bitLimit)
+	// +1 byte for the delimiter bit
+	if byteLimit := (bitLimit >> 3) + 1; byteLen > byteLimit {
+		return dr.Errorf("bitlist is too big: %d bytes, limit is %d (bitlimit %d)", byteLen, byteLimit, bitLimit)
 	}
 	// grow the destination if necessary
 	if uint64(cap(*dst)) < byteLen {
@@ -295,12 +424,15 @@ func (dr *DecodingReader) BitList(dst *[]byte, bitLimit uint64) error {
 	if _, err := dr.Read(*dst); err != nil {
 		return err
 	}
-	return bitfields.BitlistCheck(*dst, bitLimit)
+	if err := bitfields.BitlistCheck(*dst, bitLimit); err != nil {
+		return dr.Errorf("invalid bitlist: %w", err)
+	}
+	return nil
 }
 
 func (dr *DecodingReader) ByteVector(dst *[]byte, byteLength uint64) error {
 	if dst == nil {
-		return fmt.Errorf("byte vector destination is nil")
+		return dr.Errorf("byte vector destination is nil")
 	}
 	// grow the destination if necessary
 	if uint64(cap(*dst)) < byteLength {
@@ -315,7 +447,7 @@ func (dr *DecodingReader) ByteVector(dst *[]byte, byteLength uint64) error {
 func (dr *DecodingReader) ByteList(dst *[]byte, byteLimit uint64) error {
 	byteLen := dr.Scope()
 	if byteLen > byteLimit {
-		return fmt.Errorf("byte list is too big: %d bytes, limit is %d", byteLen, byteLimit)
+		return dr.Errorf("byte list is too big: %d bytes, limit is %d", byteLen, byteLimit)
 	}
 	// grow the destination if necessary
 	if uint64(cap(*dst)) < byteLen {
@@ -332,7 +464,7 @@ func (dr *DecodingReader) ByteList(dst *[]byte, byteLimit uint64) error {
 func (dr *DecodingReader) FixedLenContainer(fields ...Deserializable) error {
 	for i, f := range fields {
 		if err := f.Deserialize(dr); err != nil {
-			return fmt.Errorf("failed to deserialize fixed-length field %d: %v", i, err)
+			return dr.WrapIndex(uint64(i), err)
 		}
 	}
 	return nil
@@ -342,6 +474,7 @@ func (dr *DecodingReader) Container(fields ...Deserializable) error {
 	scope := dr.Scope()
 	var offsets []uint64
 	var dynFields []Deserializable
+	var dynIndices []uint64
 	var prev uint64
 	for i, f := range fields {
 		if fix := f.FixedLength(); fix != 0 {
@@ -350,16 +483,17 @@ func (dr *DecodingReader) Container(fields ...Deserializable) error {
 				return err
 			}
 			if err := f.Deserialize(sub); err != nil {
-				return fmt.Errorf("failed to deserialize fixed-length field %d: %v", i, err)
+				return dr.WrapIndex(uint64(i), err)
 			}
 			prev += fix
 		} else {
 			off, err := dr.ReadOffset()
 			if err != nil {
-				return fmt.Errorf("failed to read offset for field %d: %v", i, err)
+				return dr.WrapIndex(uint64(i), err)
 			}
 			offsets = append(offsets, uint64(off))
 			dynFields = append(dynFields, f)
+			dynIndices = append(dynIndices, uint64(i))
 			prev += 4
 		}
 	}
@@ -367,7 +501,7 @@ func (dr *DecodingReader) Container(fields ...Deserializable) error {
 		return nil
 	}
 	if prev != offsets[0] {
-		return fmt.Errorf("offset 0 is incorrect, expected %d, got %d", prev, offsets[0])
+		return dr.Errorf("offset 0 is incorrect, expected %d, got %d", prev, offsets[0])
 	}
 	for i, off := range offsets {
 		f := dynFields[i]
@@ -376,14 +510,14 @@ func (dr *DecodingReader) Container(fields ...Deserializable) error {
 			next = offsets[i+1]
 		}
 		if next < off {
-			return fmt.Errorf("scope cannot be negative, got offset %d after %d, at index %d", next, off, i)
+			return dr.Errorf("scope cannot be negative, got offset %d after %d, at index %d", next, off, i)
 		}
 		sub, err := dr.SubScope(next - off)
 		if err != nil {
 			return err
 		}
 		if err := f.Deserialize(sub); err != nil {
-			return fmt.Errorf("failed to deserialize dynamic-length field %d: %v", i, err)
+			return dr.WrapIndex(dynIndices[i], err)
 		}
 		prev = next
 	}
@@ -395,15 +529,15 @@ func (dr *DecodingReader) Container(fields ...Deserializable) error {
 func (dr *DecodingReader) Union(selectFn func(selector uint8) (Deserializable, error)) error {
 	selector, err := dr.ReadByte()
 	if err != nil {
-		return fmt.Errorf("fialed to read union selector: %v", err)
+		return err
 	}
 	dest, err := selectFn(selector)
 	if err != nil {
-		return fmt.Errorf("failed to select union option, with selector %d: %v", selector, err)
+		return dr.Errorf("failed to select union option, with selector %d: %w", selector, err)
 	}
 	if dest == nil {
 		if selector != 0 {
-			return fmt.Errorf("only 0 the selector can indicate a None value")
+			return dr.Errorf("only 0 the selector can indicate a None value")
 		}
 		return nil
 	}
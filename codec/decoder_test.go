@@ -0,0 +1,43 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDecodingReader_BitList(t *testing.T) {
+	cases := []struct {
+		input    []byte
+		bitLimit uint64
+		valid    bool
+	}{
+		{[]byte{0x01}, 0, true},
+		{[]byte{0x80}, 7, true},
+		{[]byte{0x00, 0x01}, 7, false},
+		// a limit that is a multiple of 8 needs an extra byte for the delimiter bit
+		{[]byte{0x01}, 8, true},
+		{[]byte{0xff, 0x01}, 8, true},
+		{[]byte{0xff, 0x02}, 8, false},
+		{[]byte{0xff, 0xff, 0x01}, 16, true},
+		{[]byte{0xff, 0xff, 0x00, 0x01}, 16, false},
+		{[]byte{0xff, 0x00}, 16, false},
+		{[]byte{}, 8, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%x_%d", c.input, c.bitLimit), func(t *testing.T) {
+			var dst []byte
+			err := NewBytesDecodingReader(c.input).BitList(&dst, c.bitLimit)
+			if c.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !bytes.Equal(dst, c.input) {
+					t.Fatalf("decoded %x, expected %x", dst, c.input)
+				}
+			} else if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
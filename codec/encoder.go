@@ -41,7 +41,10 @@ type Encodable interface {
 }
 
 type EncodingWriter struct {
-	w       io.Writer
+	// nil if the writer encodes to buf instead.
+	w io.Writer
+	// the output, when encoding to memory. Writes are appended.
+	buf     []byte
 	n       int
 	Scratch [32]byte
 }
@@ -50,6 +53,17 @@ func NewEncodingWriter(w io.Writer) *EncodingWriter {
 	return &EncodingWriter{w: w, n: 0}
 }
 
+// NewBytesEncodingWriter encodes to memory, appending to the given buffer.
+// Writes are plain copies, no io.Writer is involved. See Bytes to get the output.
+func NewBytesEncodingWriter(buf []byte) *EncodingWriter {
+	return &EncodingWriter{buf: buf, n: 0}
+}
+
+// Bytes returns the output buffer, when encoding to memory. Nil otherwise.
+func (ew *EncodingWriter) Bytes() []byte {
+	return ew.buf
+}
+
 // How many bytes were written to the underlying io.Writer before ending encoding (for handling errors)
 func (ew *EncodingWriter) Written() int {
 	return ew.n
@@ -57,6 +71,11 @@ func (ew *EncodingWriter) Written() int {
 
 // Write writes len(p) bytes from p fully to the underlying accumulated buffer.
 func (ew *EncodingWriter) Write(p []byte) error {
+	if ew.w == nil {
+		ew.buf = append(ew.buf, p...)
+		ew.n += len(p)
+		return nil
+	}
 	n := 0
 	for n < len(p) {
 		d, err := ew.w.Write(p[n:])
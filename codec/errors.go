@@ -0,0 +1,63 @@
+package codec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathElem is a single step in the path to a value: a container field name,
+// or an element index if the field name is empty.
+type PathElem struct {
+	Field string
+	Index uint64
+}
+
+func (p PathElem) String() string {
+	if p.Field != "" {
+		return p.Field
+	}
+	return "[" + strconv.FormatUint(p.Index, 10) + "]"
+}
+
+// DecodeError is a decoding failure, annotated with the absolute byte offset in the input where it occurred,
+// and the path of fields and element indices (outermost first) to the value that failed to decode.
+type DecodeError struct {
+	Offset uint64
+	Path   []PathElem
+	Err    error
+}
+
+// PathString formats the path like "validators[3].pubkey"
+func (e *DecodeError) PathString() string {
+	var buf strings.Builder
+	for i, p := range e.Path {
+		if i > 0 && p.Field != "" {
+			buf.WriteRune('.')
+		}
+		buf.WriteString(p.String())
+	}
+	return buf.String()
+}
+
+func (e *DecodeError) Error() string {
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("decoding error at byte %d: %v", e.Offset, e.Err)
+	}
+	return fmt.Sprintf("decoding error at byte %d, %s: %v", e.Offset, e.PathString(), e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func wrapPath(elem PathElem, offset uint64, err error) error {
+	if err == nil {
+		return nil
+	}
+	if de, ok := err.(*DecodeError); ok {
+		de.Path = append([]PathElem{elem}, de.Path...)
+		return de
+	}
+	return &DecodeError{Offset: offset, Path: []PathElem{elem}, Err: err}
+}
@@ -1,6 +1,7 @@
 package conv
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/holiman/uint256"
@@ -39,6 +40,37 @@ func Uint256Unmarshal(v *uint256.Int, b []byte) error {
 	return nil
 }
 
+// Uint128Unmarshal parses a uint128, with or without quotes, in any base,
+// with common prefixes accepted to change base.
+// The result is stored as two uint64 limbs, least significant first.
+func Uint128Unmarshal(v *[2]uint64, b []byte) error {
+	if v == nil {
+		return DestNilErr
+	}
+	if len(b) == 0 {
+		return EmptyInputErr
+	}
+	if b[0] == '"' {
+		if len(b) == 1 || b[len(b)-1] != b[0] {
+			return MissingQuoteErr
+		}
+		b = b[1 : len(b)-1]
+	}
+	x := new(big.Int)
+	err := x.UnmarshalText(b)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal uint128: %w", err)
+	}
+	if x.Sign() < 0 || x.BitLen() > 128 {
+		return strconv.ErrRange
+	}
+	var tmp [16]byte
+	x.FillBytes(tmp[:])
+	v[0] = binary.BigEndian.Uint64(tmp[8:16])
+	v[1] = binary.BigEndian.Uint64(tmp[0:8])
+	return nil
+}
+
 // Parse a uint of bitSize bits into a uint64, with or without quotes, in any base,
 // with common prefixes accepted to change base.
 func uintUnmarshal(v *uint64, b []byte, bitSize int) error {
@@ -98,6 +130,17 @@ func Uint256Marshal(v *uint256.Int) ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%d\"", v)), nil
 }
 
+// Uint128Marshal to decimal number, with quotes. The value is given as two uint64 limbs, least significant first.
+func Uint128Marshal(v [2]uint64) ([]byte, error) {
+	if v[1] == 0 {
+		return Uint64Marshal(v[0])
+	}
+	x := new(big.Int).SetUint64(v[1])
+	x.Lsh(x, 64)
+	x.Or(x, new(big.Int).SetUint64(v[0]))
+	return []byte(fmt.Sprintf("\"%d\"", x)), nil
+}
+
 // Uint64Marshal to decimal number, with quotes
 func Uint64Marshal(v uint64) ([]byte, error) {
 	var dest [22]byte // ceil(log10(2**64)) + 2 = 22
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"github.com/protolambda/ztyp/codec"
 	"github.com/protolambda/ztyp/tree"
 	. "github.com/protolambda/ztyp/view"
 	"testing"
@@ -74,3 +76,106 @@ func BenchmarkRegHash(t *testing.B) {
 	}
 	t.Logf("res %d", res)
 }
+
+// The registry makes up the bulk of a BeaconState
+var RegistryStateType = ContainerType("RegistryState", []FieldDef{
+	{Name: "validators", Type: RegistryValidatorsType},
+	{Name: "balances", Type: RegistryBalancesType},
+})
+
+func registryStateBytes(t *testing.B, count int) []byte {
+	validators := make([]View, count, count)
+	balances := make([]BasicView, count, count)
+	for i := 0; i < count; i++ {
+		val := ValidatorType.New()
+		if err := val.Set(2, Uint64View(32000000000)); err != nil {
+			t.Fatal(err)
+		}
+		if err := val.Set(5, Uint64View(i)); err != nil {
+			t.Fatal(err)
+		}
+		validators[i] = val
+		balances[i] = Uint64View(32000000000 + i)
+	}
+	regView, err := RegistryValidatorsType.FromElements(validators...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balView, err := RegistryBalancesType.FromElements(balances...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := RegistryStateType.FromFields(regView, balView)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := state.Serialize(codec.NewEncodingWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkRegDecodeReader(t *testing.B) {
+	data := registryStateBytes(t, 100000)
+	t.SetBytes(int64(len(data)))
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if _, err := RegistryStateType.Deserialize(codec.NewDecodingReader(bytes.NewReader(data), uint64(len(data)))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRegDecodeBytes(t *testing.B) {
+	data := registryStateBytes(t, 100000)
+	t.SetBytes(int64(len(data)))
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if _, err := RegistryStateType.Deserialize(codec.NewBytesDecodingReader(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func registryBalances(t *testing.B, count int) *BasicListView {
+	balances := make([]uint64, count, count)
+	for i := range balances {
+		balances[i] = 32000000000 + uint64(i)
+	}
+	balView, err := RegistryBalancesType.FromUint64s(balances)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balView
+}
+
+func BenchmarkRegBalancesGet(t *testing.B) {
+	balView := registryBalances(t, 100000)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		sum := uint64(0)
+		for j := uint64(0); j < 100000; j++ {
+			v, err := AsUint64(balView.Get(j))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum += uint64(v)
+		}
+	}
+}
+
+func BenchmarkRegBalancesBulk(t *testing.B) {
+	balView := registryBalances(t, 100000)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		balances, err := balView.Uint64s()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := uint64(0)
+		for _, v := range balances {
+			sum += v
+		}
+	}
+}
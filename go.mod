@@ -1,5 +1,5 @@
 module github.com/protolambda/ztyp
 
-go 1.16
+go 1.18
 
 require github.com/holiman/uint256 v1.2.0
@@ -0,0 +1,95 @@
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTRoot is a named tree to export with WriteDOT, e.g. the state of a fork.
+type DOTRoot struct {
+	Name string
+	Node Node
+}
+
+// WriteDOT exports the trees as a Graphviz DOT digraph, to visualize how the trees share nodes.
+// Every root is drawn as an ellipse with an edge to its tree.
+// Every node in memory is drawn once, with the short root, and the gindex of its first occurrence
+// (depth-first, in the order of the roots). Nodes that are reached multiple times thus have multiple incoming edges.
+// Zero-hash subtrees are collapsed into a single node per depth.
+func WriteDOT(w io.Writer, h HashFn, roots ...DOTRoot) error {
+	d := dotWriter{
+		h:     h,
+		ids:   make(map[Node]string),
+		zeros: make(map[Root]int, len(ZeroHashes)),
+	}
+	for i := range ZeroHashes {
+		d.zeros[ZeroHashes[i]] = i
+	}
+	d.line("digraph tree {")
+	d.line("  ordering=out;")
+	d.line("  node [shape=box, fontname=\"monospace\"];")
+	for i, r := range roots {
+		rootID := fmt.Sprintf("root%d", i)
+		d.line("  %s [label=\"%s\", shape=ellipse];", rootID, dotEscape(r.Name))
+		if r.Node == nil {
+			continue
+		}
+		d.line("  %s -> %s;", rootID, d.node(r.Node, 1))
+	}
+	d.line("}")
+	for _, line := range d.lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dotWriter struct {
+	h     HashFn
+	ids   map[Node]string
+	zeros map[Root]int
+	count int
+	lines []string
+}
+
+func (d *dotWriter) line(format string, args ...interface{}) {
+	d.lines = append(d.lines, fmt.Sprintf(format, args...)+"\n")
+}
+
+// node draws the node and its subtree, if it was not drawn already, and returns the ID of the node.
+func (d *dotWriter) node(n Node, g Gindex64) string {
+	if id, ok := d.ids[n]; ok {
+		return id
+	}
+	root := n.MerkleRoot(d.h)
+	if depth, ok := d.zeros[root]; ok {
+		id := fmt.Sprintf("zero%d", depth)
+		if _, ok := d.ids[&ZeroHashes[depth]]; !ok {
+			d.ids[&ZeroHashes[depth]] = id
+			d.line("  %s [label=\"zero[%d]\", style=dashed];", id, depth)
+		}
+		d.ids[n] = id
+		return id
+	}
+	id := fmt.Sprintf("n%d", d.count)
+	d.count++
+	d.ids[n] = id
+	if n.IsLeaf() {
+		d.line("  %s [label=\"g=%d\\n%s\"];", id, g, root.TerminalString())
+		return id
+	}
+	d.line("  %s [label=\"g=%d\\n%s\", style=rounded];", id, g, root.TerminalString())
+	if left, err := n.Left(); err == nil {
+		d.line("  %s -> %s;", id, d.node(left, g.Concat(LeftGindex)))
+	}
+	if right, err := n.Right(); err == nil {
+		d.line("  %s -> %s;", id, d.node(right, g.Concat(RightGindex)))
+	}
+	return id
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
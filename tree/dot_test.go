@@ -0,0 +1,57 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	a := &Root{0: 1}
+	b := &Root{0: 2}
+	pre := NewPairNode(NewPairNode(a, b), NewPairNode(ZeroNode(0), ZeroNode(0)))
+	setter, err := pre.Setter(Gindex64(6), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := setter(&Root{0: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := WriteDOT(&buf, GetHashFn(), DOTRoot{Name: "pre", Node: pre}, DOTRoot{Name: `"post"`, Node: post}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	expected := []string{
+		"digraph tree {",
+		"  ordering=out;",
+		"  node [shape=box, fontname=\"monospace\"];",
+		"  root0 [label=\"pre\", shape=ellipse];",
+		"  n0 [label=\"g=1\\n" + pre.MerkleRoot(Hash).TerminalString() + "\", style=rounded];",
+		"  n1 [label=\"g=2\\n" + pre.LeftChild.MerkleRoot(Hash).TerminalString() + "\", style=rounded];",
+		"  n2 [label=\"g=4\\n" + a.TerminalString() + "\"];",
+		"  n1 -> n2;",
+		"  n3 [label=\"g=5\\n" + b.TerminalString() + "\"];",
+		"  n1 -> n3;",
+		"  n0 -> n1;",
+		"  zero1 [label=\"zero[1]\", style=dashed];",
+		"  n0 -> zero1;",
+		"  root0 -> n0;",
+		"  root1 [label=\"\\\"post\\\"\", shape=ellipse];",
+		"  n4 [label=\"g=1\\n" + post.MerkleRoot(Hash).TerminalString() + "\", style=rounded];",
+		// the left subtree is shared with pre
+		"  n4 -> n1;",
+		"  n5 [label=\"g=3\\n" + post.(*PairNode).RightChild.MerkleRoot(Hash).TerminalString() + "\", style=rounded];",
+		"  n6 [label=\"g=6\\n" + (&Root{0: 3}).TerminalString() + "\"];",
+		"  n5 -> n6;",
+		"  zero0 [label=\"zero[0]\", style=dashed];",
+		"  n5 -> zero0;",
+		"  n4 -> n5;",
+		"  root1 -> n4;",
+		"}",
+		"",
+	}
+	if out != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected DOT output:\n%s", out)
+	}
+}
@@ -0,0 +1,109 @@
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NodeDumpOptions limits and annotates the output of DumpNode.
+type NodeDumpOptions struct {
+	// MaxDepth is the deepest level of nodes to print, relative to the dumped node.
+	// Deeper subtrees are printed as a single line with their root. 0 for no limit.
+	MaxDepth uint32
+	// BottomDepth is the depth at which the bottom nodes of the tree are expected.
+	// Leaf nodes above it are marked as summarized. 0 to not mark summarized nodes.
+	BottomDepth uint32
+}
+
+// DumpNode writes an indented tree of the nodes, one line per node, with the gindex relative to n and the root.
+// Gindices that do not fit in 64 bits are printed as 0.
+// Pair nodes are marked with whether their root was cached, or had to be computed (and is cached now).
+// Nodes that occur multiple times in the tree are printed once, other occurrences are marked as shared.
+// Zero-hash nodes (see ZeroNode) are marked with their depth.
+// The options may be nil, to print all nodes.
+func DumpNode(w io.Writer, n Node, h HashFn, opts *NodeDumpOptions) error {
+	if opts == nil {
+		opts = &NodeDumpOptions{}
+	}
+	d := nodeDumper{h: h, opts: opts, seen: make(map[Node]Gindex64)}
+	d.dump(n, 1, 0)
+	for _, line := range d.lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type nodeDumper struct {
+	h     HashFn
+	opts  *NodeDumpOptions
+	seen  map[Node]Gindex64
+	lines []string
+}
+
+func (d *nodeDumper) dump(n Node, g Gindex64, depth uint32) {
+	indent := strings.Repeat("  ", int(depth))
+	if n == nil {
+		d.lines = append(d.lines, fmt.Sprintf("%sg=%d nil\n", indent, g))
+		return
+	}
+	if zeroDepth, ok := zeroHashDepth(n); ok {
+		d.lines = append(d.lines, fmt.Sprintf("%sg=%d zero[%d] %s\n", indent, g, zeroDepth, n.MerkleRoot(d.h)))
+		return
+	}
+	if prev, ok := d.seen[n]; ok {
+		d.lines = append(d.lines, fmt.Sprintf("%sg=%d shared with g=%d %s\n", indent, g, prev, n.MerkleRoot(d.h)))
+		return
+	}
+	d.seen[n] = g
+	if n.IsLeaf() {
+		line := fmt.Sprintf("%sg=%d leaf %s", indent, g, n.MerkleRoot(d.h))
+		if depth < d.opts.BottomDepth {
+			line += " summarized"
+		}
+		d.lines = append(d.lines, line+"\n")
+		return
+	}
+	// check the cache before the children are hashed, the line is written after
+	cached := false
+	if p, ok := n.(*PairNode); ok {
+		cached = p.Value != (Root{})
+	}
+	i := len(d.lines)
+	d.lines = append(d.lines, "")
+	if d.opts.MaxDepth != 0 && depth >= d.opts.MaxDepth {
+		d.lines[i] = fmt.Sprintf("%sg=%d subtree %s\n", indent, g, n.MerkleRoot(d.h))
+		return
+	}
+	if left, err := n.Left(); err != nil {
+		d.lines = append(d.lines, fmt.Sprintf("%s  g=%d error: %v\n", indent, g.Concat(LeftGindex), err))
+	} else {
+		d.dump(left, g.Concat(LeftGindex), depth+1)
+	}
+	if right, err := n.Right(); err != nil {
+		d.lines = append(d.lines, fmt.Sprintf("%s  g=%d error: %v\n", indent, g.Concat(RightGindex), err))
+	} else {
+		d.dump(right, g.Concat(RightGindex), depth+1)
+	}
+	status := "computed"
+	if cached {
+		status = "cached"
+	}
+	d.lines[i] = fmt.Sprintf("%sg=%d pair %s %s\n", indent, g, n.MerkleRoot(d.h), status)
+}
+
+// zeroHashDepth returns the depth of the zero subtree that the node summarizes, if it is a ZeroNode.
+func zeroHashDepth(n Node) (uint32, bool) {
+	r, ok := n.(*Root)
+	if !ok {
+		return 0, false
+	}
+	for i := range ZeroHashes {
+		if r == &ZeroHashes[i] {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,56 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpNode(t *testing.T) {
+	leaf := &Root{0: 1}
+	shared := NewPairNode(leaf, ZeroNode(0))
+	n := NewPairNode(NewPairNode(shared, shared), ZeroNode(1))
+	var buf strings.Builder
+	if err := DumpNode(&buf, n, GetHashFn(), nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"g=1 pair " + n.MerkleRoot(Hash).String() + " computed",
+		"  g=2 pair " + n.LeftChild.MerkleRoot(Hash).String() + " computed",
+		"    g=4 pair " + shared.MerkleRoot(Hash).String() + " computed",
+		"      g=8 leaf " + leaf.String(),
+		"      g=9 zero[0] " + ZeroHashes[0].String(),
+		"    g=5 shared with g=4 " + shared.MerkleRoot(Hash).String(),
+		"  g=3 zero[1] " + ZeroHashes[1].String(),
+		"",
+	}
+	if got := buf.String(); got != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected dump:\n%s", got)
+	}
+
+	// roots are cached now, and the output is limited
+	buf.Reset()
+	if err := DumpNode(&buf, n, GetHashFn(), &NodeDumpOptions{MaxDepth: 1, BottomDepth: 2}); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"g=1 pair " + n.MerkleRoot(Hash).String() + " cached",
+		"  g=2 subtree " + n.LeftChild.MerkleRoot(Hash).String(),
+		"  g=3 zero[1] " + ZeroHashes[1].String(),
+		"",
+	}
+	if got := buf.String(); got != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected limited dump:\n%s", got)
+	}
+
+	buf.Reset()
+	summarized := NewPairNode(leaf, shared)
+	if err := DumpNode(&buf, summarized, GetHashFn(), &NodeDumpOptions{BottomDepth: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "  g=2 leaf "+leaf.String()+" summarized\n") {
+		t.Fatalf("expected summarized leaf:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "g=6 leaf "+leaf.String()+" summarized") {
+		t.Fatalf("bottom leaf must not be summarized:\n%s", buf.String())
+	}
+}
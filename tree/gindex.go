@@ -153,6 +153,19 @@ func (v Gindex64) LeftAlignedBigEndian() (data []byte, bitLen uint32) {
 	return out[:(bitLen8+7)>>3], uint32(bitLen8)
 }
 
+// Concat returns the gindex of sub relative to the root that v is relative to, where sub is relative to v.
+// The result is 0 (an invalid gindex) if v or sub is 0, or if the result does not fit in 64 bits.
+func (v Gindex64) Concat(sub Gindex64) Gindex64 {
+	if v == 0 || sub == 0 {
+		return 0
+	}
+	depth := sub.Depth()
+	if v.Depth()+depth >= 64 {
+		return 0
+	}
+	return v<<depth | (sub ^ (1 << depth))
+}
+
 func ToGindex64(index uint64, depth uint8) (Gindex64, error) {
 	if depth >= 64 {
 		return 0, fmt.Errorf("depth %d is too deep for Gindex64", depth)
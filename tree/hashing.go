@@ -57,6 +57,23 @@ func (h HashFn) ComplexListHTR(series SeriesHTR, length uint64, limit uint64) Ro
 	}), length)
 }
 
+// ProgressiveListHTR is like ComplexListHTR, but merkleizes the elements progressively (EIP-7916), without limit.
+func (h HashFn) ProgressiveListHTR(series SeriesHTR, length uint64) Root {
+	return h.Mixin(MerkleizeProgressive(h, length, func(i uint64) Root {
+		htr := series(i)
+		if htr == nil { // missing element? Fine, just like an empty node then
+			return Root{}
+		}
+		return htr.HashTreeRoot(h)
+	}), length)
+}
+
+// ProgressiveChunksHTR is like ChunksHTR, but merkleizes the chunks progressively (EIP-7916), without limit.
+// No length mixin is performed.
+func (h HashFn) ProgressiveChunksHTR(chunks ChunksHTR, length uint64) Root {
+	return MerkleizeProgressive(h, length, chunks)
+}
+
 func (h HashFn) Mixin(v Root, length uint64) Root {
 	var mixin Root
 	binary.LittleEndian.PutUint64(mixin[:], length)
@@ -0,0 +1,93 @@
+package tree
+
+import "fmt"
+
+// Progressive merkleization (EIP-7916) splits chunks into stages of 1, 4, 16, 64, ... chunks.
+// Every stage is a pair node: the left child holds the next stages (a zero root if there are no more chunks),
+// and the right child is the balanced subtree of the chunks of the stage.
+// An empty progressive tree is a single zero root.
+
+// ProgressiveMaxStage is the last stage that chunks can be located in, to keep gindices within 64 bits.
+const ProgressiveMaxStage = 20
+
+// ProgressiveMaxChunks is the number of chunks that fit in all stages up to and including ProgressiveMaxStage.
+const ProgressiveMaxChunks = ((uint64(1) << (2 * (ProgressiveMaxStage + 1))) - 1) / 3
+
+// ProgressiveStage returns the stage that chunk i is located in, and the index of the first chunk of that stage.
+// The subtree of the stage is 2*stage deep.
+func ProgressiveStage(i uint64) (stage uint8, start uint64) {
+	size := uint64(1)
+	for i >= start+size {
+		start += size
+		size <<= 2
+		stage += 1
+	}
+	return
+}
+
+// ProgressiveStageGindex returns the gindex of the pair node of the given stage,
+// relative to the root of the progressive tree.
+func ProgressiveStageGindex(stage uint8) (Gindex64, error) {
+	if stage > ProgressiveMaxStage {
+		return 0, fmt.Errorf("progressive stage %d is too deep, max stage is %d", stage, ProgressiveMaxStage)
+	}
+	return Gindex64(uint64(1) << stage), nil
+}
+
+// ProgressiveGindex returns the gindex of chunk i, relative to the root of the progressive tree.
+func ProgressiveGindex(i uint64) (Gindex64, error) {
+	stage, start := ProgressiveStage(i)
+	g, err := ProgressiveStageGindex(stage)
+	if err != nil {
+		return 0, err
+	}
+	// step into the stage subtree, then down to the chunk
+	return (g<<1|1)<<(2*stage) | Gindex64(i-start), nil
+}
+
+// ProgressiveFillToContents creates a progressive tree with the given nodes as chunks.
+func ProgressiveFillToContents(nodes []Node) (Node, error) {
+	if uint64(len(nodes)) > ProgressiveMaxChunks {
+		return nil, fmt.Errorf("too many nodes for progressive tree: %d", len(nodes))
+	}
+	return progressiveFill(nodes, 1, 0)
+}
+
+func progressiveFill(nodes []Node, numLeaves uint64, depth uint8) (Node, error) {
+	if len(nodes) == 0 {
+		return &ZeroHashes[0], nil
+	}
+	n := uint64(len(nodes))
+	if n > numLeaves {
+		n = numLeaves
+	}
+	subtree, err := SubtreeFillToContents(nodes[:n], depth)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := progressiveFill(nodes[n:], numLeaves<<2, depth+2)
+	if err != nil {
+		return nil, err
+	}
+	return NewPairNode(rest, subtree), nil
+}
+
+// MerkleizeProgressive merkleizes count chunks progressively, without constructing a tree.
+func MerkleizeProgressive(hasher HashFn, count uint64, leaf func(i uint64) Root) Root {
+	return merkleizeProgressive(hasher, 0, count, 1, leaf)
+}
+
+func merkleizeProgressive(hasher HashFn, start uint64, count uint64, numLeaves uint64, leaf func(i uint64) Root) Root {
+	if start >= count {
+		return Root{}
+	}
+	n := count - start
+	if n > numLeaves {
+		n = numLeaves
+	}
+	stage := Merkleize(hasher, n, numLeaves, func(i uint64) Root {
+		return leaf(start + i)
+	})
+	rest := merkleizeProgressive(hasher, start+n, count, numLeaves<<2, leaf)
+	return hasher(rest, stage)
+}
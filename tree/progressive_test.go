@@ -0,0 +1,90 @@
+package tree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMerkleizeProgressive(t *testing.T) {
+	h := GetHashFn()
+	leaf := func(i uint64) Root {
+		return Root{0: byte(i), 1: byte(i >> 8), 31: 0xff}
+	}
+	// 2 chunks: the first in stage 0, the second in stage 1 (4 chunks)
+	expected := h(h(Root{}, h(h(leaf(1), Root{}), ZeroHashes[1])), leaf(0))
+	if got := MerkleizeProgressive(h, 2, leaf); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if got := MerkleizeProgressive(h, 0, leaf); got != (Root{}) {
+		t.Fatalf("expected zero root for no chunks, got %s", got)
+	}
+	// computed with the merkleize_progressive pseudocode of EIP-7916
+	vectors := []struct {
+		count uint64
+		root  string
+	}{
+		{1, "583b37603e3276cb065f1de4360714e305874c8ec03af63c381792750278f397"},
+		{2, "dfa2e36acfe461bc1ac8c6dca78659939b3226ecfb849146f58267efe6235b1b"},
+		{5, "7081ad04dff9b759d3f7a2533765f8821d9f26020dc1e197478e4249c4a0a3b4"},
+		{21, "727ce5a9c3719488332e29b3714b4beceb643d3b6213d47201f889ad8ecdf428"},
+		{22, "fe8a7ef2d4d4244c1cbe157a95eb43977e13a694e30744f7622065599d080d9f"},
+		{85, "4dafb1ecf9541d6d3b642ee72d1bcb267e491deb687ecd38f5ca17e6e11b2d18"},
+		{86, "22f2f0a64385b68c4133267e5d9c82fbc1e2f8ceedd78130b35d070d4c28f4b0"},
+	}
+	for _, v := range vectors {
+		if got := MerkleizeProgressive(h, v.count, leaf); got.String() != "0x"+v.root {
+			t.Errorf("%d chunks: expected root 0x%s, got %s", v.count, v.root, got)
+		}
+	}
+	for _, count := range []uint64{1, 2, 4, 5, 6, 20, 21, 22, 85, 86, 200} {
+		t.Run(fmt.Sprintf("count_%d", count), func(t *testing.T) {
+			nodes := make([]Node, count, count)
+			for i := range nodes {
+				r := leaf(uint64(i))
+				nodes[i] = &r
+			}
+			node, err := ProgressiveFillToContents(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a, b := node.MerkleRoot(h), MerkleizeProgressive(h, count, leaf); a != b {
+				t.Fatalf("tree root %s does not match merkleized root %s", a, b)
+			}
+			for i := uint64(0); i < count; i++ {
+				g, err := ProgressiveGindex(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunk, err := node.Getter(g)
+				if err != nil {
+					t.Fatalf("failed to get chunk %d at gindex %d: %v", i, g, err)
+				}
+				if chunk != nodes[i] {
+					t.Fatalf("got wrong chunk at gindex %d for index %d", g, i)
+				}
+			}
+		})
+	}
+}
+
+func TestProgressiveStage(t *testing.T) {
+	cases := []struct {
+		i     uint64
+		stage uint8
+		start uint64
+	}{
+		{0, 0, 0}, {1, 1, 1}, {4, 1, 1}, {5, 2, 5}, {20, 2, 5}, {21, 3, 21}, {85, 4, 85},
+	}
+	for _, c := range cases {
+		stage, start := ProgressiveStage(c.i)
+		if stage != c.stage || start != c.start {
+			t.Errorf("chunk %d: expected stage %d starting at %d, got stage %d starting at %d", c.i, c.stage, c.start, stage, start)
+		}
+	}
+	if _, err := ProgressiveGindex(ProgressiveMaxChunks - 1); err != nil {
+		t.Errorf("last chunk should have a gindex: %v", err)
+	}
+	if _, err := ProgressiveGindex(ProgressiveMaxChunks); err == nil {
+		t.Error("expected error for chunk beyond max stage")
+	}
+}
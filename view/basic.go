@@ -24,7 +24,7 @@ func (td UintMeta) Default(_ BackingHook) View {
 	case Uint64Type:
 		return Uint64View(0)
 	case Uint128Type:
-		return nil
+		return Uint128View{}
 	case Uint256Type:
 		return Uint256View{}
 	default:
@@ -48,7 +48,7 @@ func (td UintMeta) New() BasicView {
 	case Uint64Type:
 		return Uint64View(0)
 	case Uint128Type:
-		return nil
+		return Uint128View{}
 	case Uint256Type:
 		return Uint256View{}
 	default:
@@ -73,7 +73,9 @@ func (td UintMeta) ViewFromBacking(node Node, _ BackingHook) (View, error) {
 	case Uint64Type:
 		return Uint64View(binary.LittleEndian.Uint64(v[:8])), nil
 	case Uint128Type:
-		return nil, UnsupportedUintType
+		var out Uint128View
+		out.setBytes16(v[:16])
+		return out, nil
 	case Uint256Type:
 		var out Uint256View
 		out.setBytes32(v[:])
@@ -97,7 +99,9 @@ func (td UintMeta) BasicViewFromBacking(v *Root, i uint8) (BasicView, error) {
 	case Uint64Type:
 		return Uint64View(binary.LittleEndian.Uint64(v[8*i : 8*i+8])), nil
 	case Uint128Type:
-		return nil, UnsupportedUintType
+		var out Uint128View
+		out.setBytes16(v[16*i : 16*i+16])
+		return out, nil
 	case Uint256Type:
 		var out Uint256View
 		out.setBytes32(v[:])
@@ -155,7 +159,9 @@ func (td UintMeta) Deserialize(dr *codec.DecodingReader) (View, error) {
 		v, err := dr.ReadUint64()
 		return Uint64View(v), err
 	case Uint128Type:
-		return nil, UnsupportedUintType
+		var out Uint128View
+		err := out.Deserialize(dr)
+		return out, err
 	case Uint256Type:
 		var out Uint256View
 		err := out.Deserialize(dr)
@@ -695,7 +701,7 @@ func (td BoolMeta) Deserialize(dr *codec.DecodingReader) (View, error) {
 		return nil, err
 	}
 	if b > 1 {
-		return nil, fmt.Errorf("invalid bool value: 0x%x", b)
+		return nil, dr.Errorf("invalid bool value: 0x%x", b)
 	}
 	return BoolView(b == 1), nil
 }
@@ -794,7 +800,7 @@ func (v *BoolView) Deserialize(r *codec.DecodingReader) error {
 		return err
 	}
 	if d > 1 {
-		return fmt.Errorf("invalid bool value: 0x%x", d)
+		return r.Errorf("invalid bool value: 0x%x", d)
 	}
 	*v = BoolView(d > 0)
 	return nil
@@ -0,0 +1,337 @@
+package view
+
+import (
+	"encoding/binary"
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// Bulk access to basic lists and vectors of uint64, uint32 and byte elements, with native Go slices.
+// Reading copies the packed chunks of the range directly, and writing builds the tree in a single pass.
+
+func checkPackedElemType(typ BasicTypeDef, expected BasicTypeDef) error {
+	if typ != expected {
+		return fmt.Errorf("cannot access %s elements as %s", typ.String(), expected.String())
+	}
+	return nil
+}
+
+// subtreeRangeIntoBytes copies the bottom chunks [from, to) of the subtree into dest,
+// dest starts at chunk from, and may end before the last chunk.
+func subtreeRangeIntoBytes(node Node, depth uint8, from uint64, to uint64, dest []byte) error {
+	if depth == 0 {
+		r, ok := node.(*Root)
+		if !ok {
+			return fmt.Errorf("bottom node is not a root")
+		}
+		copy(dest, r[:])
+		return nil
+	}
+	pivot := uint64(1) << (depth - 1)
+	if from < pivot {
+		left, err := node.Left()
+		if err != nil {
+			return err
+		}
+		leftTo := to
+		if leftTo > pivot {
+			leftTo = pivot
+		}
+		if err := subtreeRangeIntoBytes(left, depth-1, from, leftTo, dest); err != nil {
+			return err
+		}
+	}
+	if to > pivot {
+		right, err := node.Right()
+		if err != nil {
+			return err
+		}
+		rightFrom := from
+		if rightFrom < pivot {
+			rightFrom = pivot
+		}
+		return subtreeRangeIntoBytes(right, depth-1, rightFrom-pivot, to-pivot, dest[(rightFrom-from)<<5:])
+	}
+	return nil
+}
+
+// readPackedRange returns the packed encoding of count elements, starting at element start.
+func readPackedRange(anchor Node, depth uint8, elemSize uint64, start uint64, count uint64) ([]byte, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	byteStart, byteEnd := start*elemSize, (start+count)*elemSize
+	chunkStart, chunkEnd := byteStart>>5, (byteEnd+31)>>5
+	buf := make([]byte, (chunkEnd-chunkStart)<<5)
+	if err := subtreeRangeIntoBytes(anchor, depth, chunkStart, chunkEnd, buf); err != nil {
+		return nil, err
+	}
+	offset := byteStart - chunkStart<<5
+	return buf[offset : offset+byteEnd-byteStart], nil
+}
+
+func packUint64s(v []uint64) []byte {
+	out := make([]byte, len(v)*8)
+	for i, x := range v {
+		binary.LittleEndian.PutUint64(out[i*8:], x)
+	}
+	return out
+}
+
+func unpackUint64s(src []byte, dst []uint64) {
+	for i := range dst {
+		dst[i] = binary.LittleEndian.Uint64(src[i*8:])
+	}
+}
+
+func packUint32s(v []uint32) []byte {
+	out := make([]byte, len(v)*4)
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(out[i*4:], x)
+	}
+	return out
+}
+
+func unpackUint32s(src []byte, dst []uint32) {
+	for i := range dst {
+		dst[i] = binary.LittleEndian.Uint32(src[i*4:])
+	}
+}
+
+func (tv *BasicListView) readPacked(expected BasicTypeDef, start uint64, count uint64) ([]byte, error) {
+	if err := checkPackedElemType(tv.ElemType, expected); err != nil {
+		return nil, err
+	}
+	length, err := tv.Length()
+	if err != nil {
+		return nil, err
+	}
+	if start+count < start || start+count > length {
+		return nil, fmt.Errorf("cannot read %d elements at index %d, list has length %d", count, start, length)
+	}
+	contentsAnchor, err := tv.BackingNode.Getter(LeftGindex)
+	if err != nil {
+		return nil, err
+	}
+	// one less depth, ignore length mix-in
+	return readPackedRange(contentsAnchor, tv.depth-1, expected.TypeByteLength(), start, count)
+}
+
+func (td *BasicListTypeDef) fromPackedOf(expected BasicTypeDef, contents []byte, length uint64) (*BasicListView, error) {
+	if err := checkPackedElemType(td.ElemType, expected); err != nil {
+		return nil, err
+	}
+	if length > td.ListLimit {
+		return nil, fmt.Errorf("expected no more than %d elements, got %d", td.ListLimit, length)
+	}
+	return td.fromPacked(contents, length)
+}
+
+func (td *BasicListTypeDef) FromUint64s(v []uint64) (*BasicListView, error) {
+	return td.fromPackedOf(Uint64Type, packUint64s(v), uint64(len(v)))
+}
+
+func (td *BasicListTypeDef) FromUint32s(v []uint32) (*BasicListView, error) {
+	return td.fromPackedOf(Uint32Type, packUint32s(v), uint64(len(v)))
+}
+
+func (td *BasicListTypeDef) FromBytes(v []byte) (*BasicListView, error) {
+	return td.fromPackedOf(ByteType, v, uint64(len(v)))
+}
+
+// ReadUint64s reads len(dst) elements, starting at index start.
+func (tv *BasicListView) ReadUint64s(start uint64, dst []uint64) error {
+	data, err := tv.readPacked(Uint64Type, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	unpackUint64s(data, dst)
+	return nil
+}
+
+// Uint64s returns all elements.
+func (tv *BasicListView) Uint64s() ([]uint64, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint64, length)
+	return out, tv.ReadUint64s(0, out)
+}
+
+// SetUint64s replaces the contents of the list with the given elements.
+func (tv *BasicListView) SetUint64s(v []uint64) error {
+	list, err := tv.FromUint64s(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(list.BackingNode)
+}
+
+// ReadUint32s reads len(dst) elements, starting at index start.
+func (tv *BasicListView) ReadUint32s(start uint64, dst []uint32) error {
+	data, err := tv.readPacked(Uint32Type, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	unpackUint32s(data, dst)
+	return nil
+}
+
+// Uint32s returns all elements.
+func (tv *BasicListView) Uint32s() ([]uint32, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint32, length)
+	return out, tv.ReadUint32s(0, out)
+}
+
+// SetUint32s replaces the contents of the list with the given elements.
+func (tv *BasicListView) SetUint32s(v []uint32) error {
+	list, err := tv.FromUint32s(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(list.BackingNode)
+}
+
+// ReadBytes reads len(dst) elements, starting at index start.
+func (tv *BasicListView) ReadBytes(start uint64, dst []byte) error {
+	data, err := tv.readPacked(ByteType, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	copy(dst, data)
+	return nil
+}
+
+// Bytes returns all elements.
+func (tv *BasicListView) Bytes() ([]byte, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, length)
+	return out, tv.ReadBytes(0, out)
+}
+
+// SetBytes replaces the contents of the list with the given elements.
+func (tv *BasicListView) SetBytes(v []byte) error {
+	list, err := tv.FromBytes(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(list.BackingNode)
+}
+
+func (tv *BasicVectorView) readPacked(expected BasicTypeDef, start uint64, count uint64) ([]byte, error) {
+	if err := checkPackedElemType(tv.ElemType, expected); err != nil {
+		return nil, err
+	}
+	if start+count < start || start+count > tv.VectorLength {
+		return nil, fmt.Errorf("cannot read %d elements at index %d, vector has length %d", count, start, tv.VectorLength)
+	}
+	return readPackedRange(tv.BackingNode, tv.depth, expected.TypeByteLength(), start, count)
+}
+
+func (td *BasicVectorTypeDef) fromPackedOf(expected BasicTypeDef, contents []byte, length uint64) (*BasicVectorView, error) {
+	if err := checkPackedElemType(td.ElemType, expected); err != nil {
+		return nil, err
+	}
+	if length > td.VectorLength {
+		return nil, fmt.Errorf("expected no more than %d elements, got %d", td.VectorLength, length)
+	}
+	return td.fromPacked(contents)
+}
+
+// FromUint64s creates a vector with the given elements, any remaining elements are zero.
+func (td *BasicVectorTypeDef) FromUint64s(v []uint64) (*BasicVectorView, error) {
+	return td.fromPackedOf(Uint64Type, packUint64s(v), uint64(len(v)))
+}
+
+// FromUint32s creates a vector with the given elements, any remaining elements are zero.
+func (td *BasicVectorTypeDef) FromUint32s(v []uint32) (*BasicVectorView, error) {
+	return td.fromPackedOf(Uint32Type, packUint32s(v), uint64(len(v)))
+}
+
+// FromBytes creates a vector with the given elements, any remaining elements are zero.
+func (td *BasicVectorTypeDef) FromBytes(v []byte) (*BasicVectorView, error) {
+	return td.fromPackedOf(ByteType, v, uint64(len(v)))
+}
+
+// ReadUint64s reads len(dst) elements, starting at index start.
+func (tv *BasicVectorView) ReadUint64s(start uint64, dst []uint64) error {
+	data, err := tv.readPacked(Uint64Type, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	unpackUint64s(data, dst)
+	return nil
+}
+
+// Uint64s returns all elements.
+func (tv *BasicVectorView) Uint64s() ([]uint64, error) {
+	out := make([]uint64, tv.VectorLength)
+	return out, tv.ReadUint64s(0, out)
+}
+
+// SetUint64s overwrites the contents of the vector, any remaining elements are set to zero.
+func (tv *BasicVectorView) SetUint64s(v []uint64) error {
+	vec, err := tv.FromUint64s(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(vec.BackingNode)
+}
+
+// ReadUint32s reads len(dst) elements, starting at index start.
+func (tv *BasicVectorView) ReadUint32s(start uint64, dst []uint32) error {
+	data, err := tv.readPacked(Uint32Type, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	unpackUint32s(data, dst)
+	return nil
+}
+
+// Uint32s returns all elements.
+func (tv *BasicVectorView) Uint32s() ([]uint32, error) {
+	out := make([]uint32, tv.VectorLength)
+	return out, tv.ReadUint32s(0, out)
+}
+
+// SetUint32s overwrites the contents of the vector, any remaining elements are set to zero.
+func (tv *BasicVectorView) SetUint32s(v []uint32) error {
+	vec, err := tv.FromUint32s(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(vec.BackingNode)
+}
+
+// ReadBytes reads len(dst) elements, starting at index start.
+func (tv *BasicVectorView) ReadBytes(start uint64, dst []byte) error {
+	data, err := tv.readPacked(ByteType, start, uint64(len(dst)))
+	if err != nil {
+		return err
+	}
+	copy(dst, data)
+	return nil
+}
+
+// Bytes returns all elements.
+func (tv *BasicVectorView) Bytes() ([]byte, error) {
+	out := make([]byte, tv.VectorLength)
+	return out, tv.ReadBytes(0, out)
+}
+
+// SetBytes overwrites the contents of the vector, any remaining elements are set to zero.
+func (tv *BasicVectorView) SetBytes(v []byte) error {
+	vec, err := tv.FromBytes(v)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(vec.BackingNode)
+}
@@ -0,0 +1,143 @@
+package view
+
+import (
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestBasicListUint64s(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BasicListType(Uint64Type, 1024)
+	for _, n := range []int{0, 1, 3, 4, 5, 100, 1024} {
+		values := make([]uint64, n)
+		expected := td.New()
+		for i := range values {
+			values[i] = uint64(i)*1000 + 7
+			if err := expected.Append(Uint64View(values[i])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		list, err := td.FromUint64s(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+			t.Fatalf("length %d: root does not match list of appended elements", n)
+		}
+		out, err := list.Uint64s()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != n {
+			t.Fatalf("expected %d elements, got %d", n, len(out))
+		}
+		for i := range out {
+			if out[i] != values[i] {
+				t.Fatalf("length %d: element %d: got %d, expected %d", n, i, out[i], values[i])
+			}
+		}
+		// ranges that do not align with chunks
+		for start := 0; start < n; start += 3 {
+			for _, count := range []int{0, 1, 2, 5} {
+				if start+count > n {
+					continue
+				}
+				dst := make([]uint64, count)
+				if err := list.ReadUint64s(uint64(start), dst); err != nil {
+					t.Fatal(err)
+				}
+				for i := range dst {
+					if dst[i] != values[start+i] {
+						t.Fatalf("range %d+%d: element %d: got %d, expected %d", start, count, i, dst[i], values[start+i])
+					}
+				}
+			}
+		}
+		if err := list.ReadUint64s(uint64(n), make([]uint64, 1)); err == nil {
+			t.Fatal("expected error for out of range read")
+		}
+	}
+	if _, err := td.FromUint64s(make([]uint64, 1025)); err == nil {
+		t.Fatal("expected error for exceeding limit")
+	}
+	if _, err := td.FromUint32s([]uint32{1}); err == nil {
+		t.Fatal("expected error for wrong element type")
+	}
+}
+
+func TestBasicListSetUint32s(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BasicListType(Uint32Type, 100)
+	list := td.New()
+	values := []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if err := list.SetUint32s(values); err != nil {
+		t.Fatal(err)
+	}
+	elems := make([]BasicView, len(values))
+	for i, v := range values {
+		elems[i] = Uint32View(v)
+	}
+	expected, err := td.FromElements(elems...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+		t.Fatal("root does not match list from elements")
+	}
+	dst := make([]uint32, 4)
+	if err := list.ReadUint32s(6, dst); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range dst {
+		if v != values[6+i] {
+			t.Fatalf("element %d: got %d, expected %d", 6+i, v, values[6+i])
+		}
+	}
+}
+
+func TestBasicVectorBytes(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BasicVectorType(ByteType, 100)
+	data := make([]byte, 70)
+	elems := make([]BasicView, 70)
+	for i := range data {
+		data[i] = byte(i + 1)
+		elems[i] = ByteView(i + 1)
+	}
+	vec := td.New()
+	if err := vec.SetBytes(data); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := td.FromElements(elems...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vec.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+		t.Fatal("root does not match vector from elements")
+	}
+	out, err := vec.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range out {
+		if i < len(data) && b != data[i] || i >= len(data) && b != 0 {
+			t.Fatalf("byte %d: unexpected %d", i, b)
+		}
+	}
+	dst := make([]byte, 40)
+	if err := vec.ReadBytes(30, dst); err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range dst {
+		if b != out[30+i] {
+			t.Fatalf("byte %d: got %d, expected %d", 30+i, b, out[30+i])
+		}
+	}
+	u64 := BasicVectorType(Uint64Type, 5).New()
+	if err := u64.SetUint64s([]uint64{5, 4, 3, 2, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := u64.Get(4); err != nil || v != Uint64View(1) {
+		t.Fatalf("expected 1, got %v: %v", v, err)
+	}
+}
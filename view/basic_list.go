@@ -100,18 +100,26 @@ func (td *BasicListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error)
 	scope := dr.Scope()
 	length := scope / elemSize
 	if length > td.ListLimit {
-		return nil, fmt.Errorf("too many items, limit %d but got %d", td.ListLimit, length)
+		return nil, dr.Errorf("too many items, limit %d but got %d", td.ListLimit, length)
 	}
 	if expected := length * elemSize; expected != scope {
-		return nil, fmt.Errorf("scope %d does not align to elem size %d", scope, elemSize)
+		return nil, dr.Errorf("scope %d does not align to elem size %d", scope, elemSize)
 	}
 	if length == 0 {
 		return td.New(), nil
 	}
-	contents := make([]byte, scope, scope)
-	if _, err := dr.Read(contents); err != nil {
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
 		return nil, err
 	}
+	return td.fromPacked(contents, length)
+}
+
+// fromPacked creates a list of length elements from the packed little-endian encoding of the elements.
+func (td *BasicListTypeDef) fromPacked(contents []byte, length uint64) (*BasicListView, error) {
+	if length == 0 {
+		return td.New(), nil
+	}
 	bottomNodes, err := BytesIntoNodes(contents)
 	if err != nil {
 		return nil, err
@@ -344,6 +352,42 @@ func (tv *BasicListView) ReadonlyIter() ElemIter {
 	return basicElemReadonlyIter(node, length, tv.depth-1, tv.ElemType)
 }
 
+// ReadonlyIterRange iterates over the elements [start, end), without visiting the subtrees of other elements.
+func (tv *BasicListView) ReadonlyIterRange(start uint64, end uint64) ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrElemIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	// ignore length mixin in stack
+	return basicElemRangeIter(node, tv.depth-1, tv.ElemType, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the elements [start, end) in reverse order, starting at end-1.
+func (tv *BasicListView) ReadonlyIterReverse(start uint64, end uint64) ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrElemIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	// ignore length mixin in stack
+	return basicElemRangeIter(node, tv.depth-1, tv.ElemType, start, end, true)
+}
+
 func (tv *BasicListView) ValueByteLength() (uint64, error) {
 	length, err := tv.Length()
 	if err != nil {
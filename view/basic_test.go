@@ -87,3 +87,45 @@ func TestUint64View_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestUint128View_JSON(t *testing.T) {
+	cases := []struct {
+		v Uint128View
+		s string
+	}{
+		{Uint128View{}, "0"},
+		{Uint128View{1234}, "1234"},
+		{Uint128View{^uint64(0)}, "18446744073709551615"},
+		{Uint128View{0, 1}, "18446744073709551616"},
+		{Uint128View{^uint64(0), ^uint64(0)}, "340282366920938463463374607431768211455"},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			out, err := c.v.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != `"`+c.s+`"` {
+				t.Errorf("unexpected value: %s", string(out))
+			}
+			var res Uint128View
+			if err := res.UnmarshalJSON(out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res != c.v {
+				t.Errorf("unexpected value: %v", res)
+			}
+			if res.ToBig().String() != c.s {
+				t.Errorf("unexpected big int: %s", res.ToBig())
+			}
+			var fromU256 Uint128View
+			if fromU256.SetFromUint256(res.ToUint256()) || fromU256 != c.v {
+				t.Errorf("uint256 round trip failed: %v", fromU256)
+			}
+		})
+	}
+	var res Uint128View
+	if err := res.UnmarshalJSON([]byte(`"340282366920938463463374607431768211456"`)); err == nil {
+		t.Error("expected overflow error")
+	}
+}
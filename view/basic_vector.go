@@ -97,12 +97,21 @@ func (td *BasicVectorTypeDef) New() *BasicVectorView {
 func (td *BasicVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	scope := dr.Scope()
 	if td.Size != scope {
-		return nil, fmt.Errorf("expected size %d does not match scope %d", td.Size, scope)
+		return nil, dr.Errorf("expected size %d does not match scope %d", td.Size, scope)
 	}
-	contents := make([]byte, scope, scope)
-	if _, err := dr.Read(contents); err != nil {
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
 		return nil, err
 	}
+	return td.fromPacked(contents)
+}
+
+// fromPacked creates a vector from the packed little-endian encoding of the elements.
+// Elements that are not included are zero.
+func (td *BasicVectorTypeDef) fromPacked(contents []byte) (*BasicVectorView, error) {
+	if len(contents) == 0 {
+		return td.New(), nil
+	}
 	bottomNodes, err := BytesIntoNodes(contents)
 	if err != nil {
 		return nil, err
@@ -192,6 +201,22 @@ func (tv *BasicVectorView) ReadonlyIter() ElemIter {
 	return basicElemReadonlyIter(tv.BackingNode, tv.VectorLength, tv.depth, tv.ElemType)
 }
 
+// ReadonlyIterRange iterates over the elements [start, end), without visiting the subtrees of other elements.
+func (tv *BasicVectorView) ReadonlyIterRange(start uint64, end uint64) ElemIter {
+	if err := checkIterRange(start, end, tv.VectorLength); err != nil {
+		return ErrElemIter{err}
+	}
+	return basicElemRangeIter(tv.BackingNode, tv.depth, tv.ElemType, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the elements [start, end) in reverse order, starting at end-1.
+func (tv *BasicVectorView) ReadonlyIterReverse(start uint64, end uint64) ElemIter {
+	if err := checkIterRange(start, end, tv.VectorLength); err != nil {
+		return ErrElemIter{err}
+	}
+	return basicElemRangeIter(tv.BackingNode, tv.depth, tv.ElemType, start, end, true)
+}
+
 func (tv *BasicVectorView) ValueByteLength() (uint64, error) {
 	return tv.Size, nil
 }
@@ -105,6 +105,17 @@ func bitReadonlyIter(anchor Node, length uint64, depth uint8) BitIter {
 	})
 }
 
+func bitRangeIter(anchor Node, depth uint8, start uint64, end uint64, reverse bool) BitIter {
+	iter := packedRangeIter(anchor, depth, 256, start, end, reverse)
+	return BitIterFn(func() (elem bool, ok bool, err error) {
+		r, sub, ok, err := iter()
+		if err != nil || !ok {
+			return false, ok, err
+		}
+		return (r[sub>>3]>>(sub&7))&1 == 1, true, nil
+	})
+}
+
 func bitsToBytes(bits []bool) []byte {
 	byteLen := (len(bits) + 7) / 8
 	out := make([]byte, byteLen, byteLen)
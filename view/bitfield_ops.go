@@ -0,0 +1,321 @@
+package view
+
+import (
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+	"math/bits"
+)
+
+// Bitwise operations on bitfield views, applied chunk by chunk on the backing roots.
+
+type chunkOp func(a *Root, b *Root) *Root
+
+func chunkOr(a *Root, b *Root) *Root {
+	var out Root
+	for i := 0; i < 32; i++ {
+		out[i] = a[i] | b[i]
+	}
+	return &out
+}
+
+func chunkAnd(a *Root, b *Root) *Root {
+	var out Root
+	for i := 0; i < 32; i++ {
+		out[i] = a[i] & b[i]
+	}
+	return &out
+}
+
+func chunkXor(a *Root, b *Root) *Root {
+	var out Root
+	for i := 0; i < 32; i++ {
+		out[i] = a[i] ^ b[i]
+	}
+	return &out
+}
+
+func nodeChildren(node Node, depth uint8) (left Node, right Node, err error) {
+	if node.IsLeaf() {
+		// a zero subtree
+		return &ZeroHashes[depth-1], &ZeroHashes[depth-1], nil
+	}
+	if left, err = node.Left(); err != nil {
+		return nil, nil, err
+	}
+	right, err = node.Right()
+	return
+}
+
+// combineChunks applies the op to the first count bottom chunks of the subtrees a and b.
+// The chunks after count are kept from a.
+func combineChunks(a Node, b Node, depth uint8, count uint64, op chunkOp) (Node, error) {
+	if count == 0 {
+		return a, nil
+	}
+	if depth == 0 {
+		x, ok := a.(*Root)
+		if !ok {
+			return nil, fmt.Errorf("bottom node is not a root")
+		}
+		y, ok := b.(*Root)
+		if !ok {
+			return nil, fmt.Errorf("bottom node is not a root")
+		}
+		return op(x, y), nil
+	}
+	aLeft, aRight, err := nodeChildren(a, depth)
+	if err != nil {
+		return nil, err
+	}
+	bLeft, bRight, err := nodeChildren(b, depth)
+	if err != nil {
+		return nil, err
+	}
+	pivot := uint64(1) << (depth - 1)
+	if count <= pivot {
+		left, err := combineChunks(aLeft, bLeft, depth-1, count, op)
+		if err != nil {
+			return nil, err
+		}
+		return NewPairNode(left, aRight), nil
+	}
+	left, err := combineChunks(aLeft, bLeft, depth-1, pivot, op)
+	if err != nil {
+		return nil, err
+	}
+	right, err := combineChunks(aRight, bRight, depth-1, count-pivot, op)
+	if err != nil {
+		return nil, err
+	}
+	return NewPairNode(left, right), nil
+}
+
+// chunksOverlap returns true if any of the first count bottom chunks of a and b have a common bit set.
+func chunksOverlap(a Node, b Node, depth uint8, count uint64) (bool, error) {
+	aIter, bIter := nodeRangeIter(a, depth, 0, count, false), nodeRangeIter(b, depth, 0, count, false)
+	for {
+		x, ok, err := aIter.Next()
+		if err != nil || !ok {
+			return false, err
+		}
+		y, _, err := bIter.Next()
+		if err != nil {
+			return false, err
+		}
+		xr, xok := x.(*Root)
+		yr, yok := y.(*Root)
+		if !xok || !yok {
+			return false, fmt.Errorf("bottom node is not a root")
+		}
+		if xr == yr {
+			// same node, only overlapping if any bit is set
+			if *xr != (Root{}) {
+				return true, nil
+			}
+			continue
+		}
+		for i := 0; i < 32; i++ {
+			if xr[i]&yr[i] != 0 {
+				return true, nil
+			}
+		}
+	}
+}
+
+func chunksOnesCount(anchor Node, depth uint8, count uint64) (uint64, error) {
+	iter := nodeRangeIter(anchor, depth, 0, count, false)
+	total := uint64(0)
+	for {
+		node, ok, err := iter.Next()
+		if err != nil || !ok {
+			return total, err
+		}
+		r, isRoot := node.(*Root)
+		if !isRoot {
+			return 0, fmt.Errorf("bottom node is not a root")
+		}
+		for _, b := range r {
+			total += uint64(bits.OnesCount8(b))
+		}
+	}
+}
+
+func chunksOnesIndices(anchor Node, depth uint8, count uint64) ([]uint64, error) {
+	iter := nodeRangeIter(anchor, depth, 0, count, false)
+	var out []uint64
+	for c := uint64(0); ; c++ {
+		node, ok, err := iter.Next()
+		if err != nil || !ok {
+			return out, err
+		}
+		r, isRoot := node.(*Root)
+		if !isRoot {
+			return nil, fmt.Errorf("bottom node is not a root")
+		}
+		for i, b := range r {
+			for b != 0 {
+				out = append(out, c<<8|uint64(i)<<3|uint64(bits.TrailingZeros8(b)))
+				b &= b - 1
+			}
+		}
+	}
+}
+
+func (tv *BitVectorView) checkSameType(other *BitVectorView) error {
+	if tv.BitLength != other.BitLength {
+		return fmt.Errorf("bitvector length mismatch: %d <> %d", tv.BitLength, other.BitLength)
+	}
+	return nil
+}
+
+func (tv *BitVectorView) combine(other *BitVectorView, op chunkOp) error {
+	if err := tv.checkSameType(other); err != nil {
+		return err
+	}
+	node, err := combineChunks(tv.BackingNode, other.BackingNode, tv.depth, tv.BottomNodeLength(), op)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(node)
+}
+
+// Or sets the bits that are set in the other bitvector.
+func (tv *BitVectorView) Or(other *BitVectorView) error {
+	return tv.combine(other, chunkOr)
+}
+
+// And clears the bits that are not set in the other bitvector.
+func (tv *BitVectorView) And(other *BitVectorView) error {
+	return tv.combine(other, chunkAnd)
+}
+
+// Xor flips the bits that are set in the other bitvector.
+func (tv *BitVectorView) Xor(other *BitVectorView) error {
+	return tv.combine(other, chunkXor)
+}
+
+// Overlaps returns true if any bit is set in both bitvectors.
+func (tv *BitVectorView) Overlaps(other *BitVectorView) (bool, error) {
+	if err := tv.checkSameType(other); err != nil {
+		return false, err
+	}
+	return chunksOverlap(tv.BackingNode, other.BackingNode, tv.depth, tv.BottomNodeLength())
+}
+
+// OnesCount returns the number of bits that are set.
+func (tv *BitVectorView) OnesCount() (uint64, error) {
+	return chunksOnesCount(tv.BackingNode, tv.depth, tv.BottomNodeLength())
+}
+
+// OnesIndices returns the indices of the bits that are set, in increasing order.
+func (tv *BitVectorView) OnesIndices() ([]uint64, error) {
+	return chunksOnesIndices(tv.BackingNode, tv.depth, tv.BottomNodeLength())
+}
+
+// contents returns the contents subtree, without length mix-in, and the number of bottom chunks in use.
+func (tv *BitListView) contents() (node Node, chunks uint64, err error) {
+	length, err := tv.Length()
+	if err != nil {
+		return nil, 0, err
+	}
+	node, err = tv.BackingNode.Left()
+	if err != nil {
+		return nil, 0, err
+	}
+	return node, (length + 0xff) >> 8, nil
+}
+
+func (tv *BitListView) checkSameType(other *BitListView) error {
+	if tv.BitLimit != other.BitLimit {
+		return fmt.Errorf("bitlist limit mismatch: %d <> %d", tv.BitLimit, other.BitLimit)
+	}
+	a, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	b, err := other.Length()
+	if err != nil {
+		return err
+	}
+	if a != b {
+		return fmt.Errorf("bitlist length mismatch: %d <> %d", a, b)
+	}
+	return nil
+}
+
+func (tv *BitListView) combine(other *BitListView, op chunkOp) error {
+	if err := tv.checkSameType(other); err != nil {
+		return err
+	}
+	a, chunks, err := tv.contents()
+	if err != nil {
+		return err
+	}
+	b, err := other.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	// one less depth, ignore length mix-in
+	node, err := combineChunks(a, b, tv.depth-1, chunks, op)
+	if err != nil {
+		return err
+	}
+	setContents, err := tv.BackingNode.Setter(LeftGindex, false)
+	if err != nil {
+		return err
+	}
+	bNode, err := setContents(node)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+// Or sets the bits that are set in the other bitlist. Both bitlists must have the same length.
+func (tv *BitListView) Or(other *BitListView) error {
+	return tv.combine(other, chunkOr)
+}
+
+// And clears the bits that are not set in the other bitlist. Both bitlists must have the same length.
+func (tv *BitListView) And(other *BitListView) error {
+	return tv.combine(other, chunkAnd)
+}
+
+// Xor flips the bits that are set in the other bitlist. Both bitlists must have the same length.
+func (tv *BitListView) Xor(other *BitListView) error {
+	return tv.combine(other, chunkXor)
+}
+
+// Overlaps returns true if any bit is set in both bitlists. Both bitlists must have the same length.
+func (tv *BitListView) Overlaps(other *BitListView) (bool, error) {
+	if err := tv.checkSameType(other); err != nil {
+		return false, err
+	}
+	a, chunks, err := tv.contents()
+	if err != nil {
+		return false, err
+	}
+	b, err := other.BackingNode.Left()
+	if err != nil {
+		return false, err
+	}
+	return chunksOverlap(a, b, tv.depth-1, chunks)
+}
+
+// OnesCount returns the number of bits that are set.
+func (tv *BitListView) OnesCount() (uint64, error) {
+	node, chunks, err := tv.contents()
+	if err != nil {
+		return 0, err
+	}
+	return chunksOnesCount(node, tv.depth-1, chunks)
+}
+
+// OnesIndices returns the indices of the bits that are set, in increasing order.
+func (tv *BitListView) OnesIndices() ([]uint64, error) {
+	node, chunks, err := tv.contents()
+	if err != nil {
+		return nil, err
+	}
+	return chunksOnesIndices(node, tv.depth-1, chunks)
+}
@@ -0,0 +1,253 @@
+package view
+
+import (
+	"bytes"
+	"github.com/protolambda/ztyp/bitfields/typed"
+	"github.com/protolambda/ztyp/tree"
+	"math/rand"
+	"testing"
+)
+
+func randomBits(rng *rand.Rand, n uint64) []bool {
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = rng.Intn(3) == 0
+	}
+	return out
+}
+
+func combineBits(a []bool, b []bool, op func(x, y bool) bool) []bool {
+	out := make([]bool, len(a))
+	for i := range out {
+		out[i] = op(a[i], b[i])
+	}
+	return out
+}
+
+func onesOf(v []bool) (out []uint64) {
+	for i, b := range v {
+		if b {
+			out = append(out, uint64(i))
+		}
+	}
+	return
+}
+
+var bitOps = []struct {
+	name string
+	op   func(x, y bool) bool
+}{
+	{"or", func(x, y bool) bool { return x || y }},
+	{"and", func(x, y bool) bool { return x && y }},
+	{"xor", func(x, y bool) bool { return x != y }},
+}
+
+func TestBitVectorOps(t *testing.T) {
+	hFn := tree.GetHashFn()
+	rng := rand.New(rand.NewSource(123))
+	for _, n := range []uint64{1, 7, 256, 300, 1000} {
+		td := BitVectorType(n)
+		a, b := randomBits(rng, n), randomBits(rng, n)
+		for _, op := range bitOps {
+			av, err := td.FromBits(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bv, err := td.FromBits(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch op.name {
+			case "or":
+				err = av.Or(bv)
+			case "and":
+				err = av.And(bv)
+			case "xor":
+				err = av.Xor(bv)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := combineBits(a, b, op.op)
+			expected, err := td.FromBits(res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if av.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+				t.Fatalf("%s of %d bits: unexpected result", op.name, n)
+			}
+			count, err := av.OnesCount()
+			if err != nil {
+				t.Fatal(err)
+			}
+			indices, err := av.OnesIndices()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedIndices := onesOf(res)
+			if count != uint64(len(expectedIndices)) || len(indices) != len(expectedIndices) {
+				t.Fatalf("%s of %d bits: got %d ones, %d indices, expected %d", op.name, n, count, len(indices), len(expectedIndices))
+			}
+			for i := range indices {
+				if indices[i] != expectedIndices[i] {
+					t.Fatalf("%s of %d bits: index %d: got %d, expected %d", op.name, n, i, indices[i], expectedIndices[i])
+				}
+			}
+		}
+		av, _ := td.FromBits(a)
+		bv, _ := td.FromBits(b)
+		overlaps, err := av.Overlaps(bv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := len(onesOf(combineBits(a, b, bitOps[1].op))) > 0; overlaps != expected {
+			t.Fatalf("overlap of %d bits: got %v, expected %v", n, overlaps, expected)
+		}
+	}
+	if err := BitVectorType(8).New().Or(BitVectorType(9).New()); err == nil {
+		t.Fatal("expected length mismatch error")
+	}
+}
+
+func TestBitListOps(t *testing.T) {
+	hFn := tree.GetHashFn()
+	rng := rand.New(rand.NewSource(456))
+	td := BitListType(2048)
+	for _, n := range []uint64{0, 1, 9, 256, 700} {
+		a, b := randomBits(rng, n), randomBits(rng, n)
+		fromBits := func(v []bool) *BitListView {
+			if len(v) == 0 {
+				return td.New()
+			}
+			out, err := td.FromBits(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return out
+		}
+		for _, op := range bitOps {
+			av, bv := fromBits(a), fromBits(b)
+			var err error
+			switch op.name {
+			case "or":
+				err = av.Or(bv)
+			case "and":
+				err = av.And(bv)
+			case "xor":
+				err = av.Xor(bv)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := combineBits(a, b, op.op)
+			if av.HashTreeRoot(hFn) != fromBits(res).HashTreeRoot(hFn) {
+				t.Fatalf("%s of %d bits: unexpected result", op.name, n)
+			}
+			if length, err := av.Length(); err != nil || length != n {
+				t.Fatalf("%s of %d bits: unexpected length %d: %v", op.name, n, length, err)
+			}
+			count, err := av.OnesCount()
+			if err != nil {
+				t.Fatal(err)
+			}
+			indices, err := av.OnesIndices()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedIndices := onesOf(res)
+			if count != uint64(len(expectedIndices)) || len(indices) != len(expectedIndices) {
+				t.Fatalf("%s of %d bits: got %d ones, %d indices, expected %d", op.name, n, count, len(indices), len(expectedIndices))
+			}
+			for i := range indices {
+				if indices[i] != expectedIndices[i] {
+					t.Fatalf("%s of %d bits: index %d: got %d, expected %d", op.name, n, i, indices[i], expectedIndices[i])
+				}
+			}
+		}
+		overlaps, err := fromBits(a).Overlaps(fromBits(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := len(onesOf(combineBits(a, b, bitOps[1].op))) > 0; overlaps != expected {
+			t.Fatalf("overlap of %d bits: got %v, expected %v", n, overlaps, expected)
+		}
+	}
+	long, err := td.FromBits([]bool{true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	short, err := td.FromBits([]bool{true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := long.And(short); err == nil {
+		t.Fatal("expected length mismatch error")
+	}
+}
+
+func TestBitfieldConversion(t *testing.T) {
+	hFn := tree.GetHashFn()
+	rng := rand.New(rand.NewSource(789))
+	for _, n := range []uint64{0, 5, 256, 700} {
+		bits := randomBits(rng, n)
+		list, err := typed.NewBitlist(0, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vec := typed.NewBitvector(n)
+		for i, b := range bits {
+			if err := list.Append(b); err != nil {
+				t.Fatal(err)
+			}
+			vec.Set(uint64(i), b)
+		}
+		listView, err := BitListType(1024).FromBitlist(list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if listView.HashTreeRoot(hFn) != list.HashTreeRoot(hFn) {
+			t.Fatalf("%d bits: bitlist view root does not match", n)
+		}
+		back, err := listView.Bitlist()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(back.Bytes(), list.Bytes()) {
+			t.Fatalf("%d bits: bitlist %x does not match %x", n, back.Bytes(), list.Bytes())
+		}
+		if n == 0 {
+			continue
+		}
+		vecView, err := BitVectorType(n).FromBitvector(vec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vecView.HashTreeRoot(hFn) != vec.HashTreeRoot(hFn) {
+			t.Fatalf("%d bits: bitvector view root does not match", n)
+		}
+		backVec, err := vecView.Bitvector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(backVec.Bytes(), vec.Bytes()) {
+			t.Fatalf("%d bits: bitvector %x does not match %x", n, backVec.Bytes(), vec.Bytes())
+		}
+	}
+	list, _ := typed.NewBitlist(0, 8)
+	if _, err := BitListType(16).FromBitlist(list); err == nil {
+		t.Fatal("expected limit mismatch error")
+	}
+}
+
+func TestChunksOverlapInvalidNode(t *testing.T) {
+	// a shared bottom node that is not a root is an error, not a panic
+	pair := tree.NewPairNode(&tree.ZeroHashes[0], &tree.ZeroHashes[0])
+	if _, err := chunksOverlap(pair, pair, 0, 1); err == nil {
+		t.Fatal("expected error for non-root bottom node")
+	}
+	var root tree.Root
+	root[3] = 1
+	if overlap, err := chunksOverlap(&root, &root, 0, 1); err != nil || !overlap {
+		t.Fatalf("expected shared root to overlap, got %v, %v", overlap, err)
+	}
+}
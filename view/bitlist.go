@@ -3,6 +3,7 @@ package view
 import (
 	"encoding/binary"
 	"fmt"
+	"github.com/protolambda/ztyp/bitfields/typed"
 	"github.com/protolambda/ztyp/codec"
 	. "github.com/protolambda/ztyp/tree"
 )
@@ -40,6 +41,14 @@ func (td *BitListTypeDef) FromBits(bits []bool) (*BitListView, error) {
 	return view.(*BitListView), nil
 }
 
+// FromBitlist creates a view of the given bitlist, which must have the same limit.
+func (td *BitListTypeDef) FromBitlist(b *typed.Bitlist) (*BitListView, error) {
+	if b.Limit() != td.BitLimit {
+		return nil, fmt.Errorf("bitlist limit %d does not match type limit %d", b.Limit(), td.BitLimit)
+	}
+	return AsBitList(td.Deserialize(codec.NewBytesDecodingReader(b.Bytes())))
+}
+
 func (td *BitListTypeDef) Limit() uint64 {
 	return td.BitLimit
 }
@@ -82,10 +91,10 @@ func (td *BitListTypeDef) New() *BitListView {
 func (td *BitListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	scope := dr.Scope()
 	if scope == 0 {
-		return nil, fmt.Errorf("expected at least a delimit bit, bitlist scope cannot be 0")
+		return nil, dr.Errorf("expected at least a delimit bit, bitlist scope cannot be 0")
 	}
 	if scope > td.MaxSize {
-		return nil, fmt.Errorf("bitlist has too many bytes, bitlimit %d (byte size %d) but got scope %d", td.BitLimit, td.MaxSize, scope)
+		return nil, dr.Errorf("bitlist has too many bytes, bitlimit %d (byte size %d) but got scope %d", td.BitLimit, td.MaxSize, scope)
 	}
 	contents := make([]byte, scope, scope)
 	if _, err := dr.Read(contents); err != nil {
@@ -93,7 +102,7 @@ func (td *BitListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	}
 	lastByte := contents[scope-1]
 	if lastByte == 0 {
-		return nil, fmt.Errorf("bitlist last byte must not be zero, delimit bit is missing")
+		return nil, dr.Errorf("bitlist last byte must not be zero, delimit bit is missing")
 	}
 	if scope == 1 && lastByte == 1 {
 		// only a delimit bit, return empty bitlist
@@ -102,7 +111,7 @@ func (td *BitListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	delimitBitIndex := ByteBitIndex(lastByte)
 	bitLen := ((scope - 1) << 3) + delimitBitIndex
 	if bitLen > td.BitLimit {
-		return nil, fmt.Errorf("bitlist has too many bits set in last byte, got bit length %d, limit is %d", bitLen, td.BitLimit)
+		return nil, dr.Errorf("bitlist has too many bits set in last byte, got bit length %d, limit is %d", bitLen, td.BitLimit)
 	}
 	// Remove delimit bit
 	if delimitBitIndex == 0 {
@@ -357,6 +366,55 @@ func (tv *BitListView) ReadonlyIter() BitIter {
 	return bitReadonlyIter(node, length, tv.depth-1)
 }
 
+// ReadonlyIterRange iterates over the bits [start, end), without visiting the subtrees of other bits.
+func (tv *BitListView) ReadonlyIterRange(start uint64, end uint64) BitIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrBitIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrBitIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrBitIter{err}
+	}
+	// ignore length mixin in stack
+	return bitRangeIter(node, tv.depth-1, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the bits [start, end) in reverse order, starting at end-1.
+func (tv *BitListView) ReadonlyIterReverse(start uint64, end uint64) BitIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrBitIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrBitIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrBitIter{err}
+	}
+	// ignore length mixin in stack
+	return bitRangeIter(node, tv.depth-1, start, end, true)
+}
+
+// Bitlist returns the bits as a packed bitlist.
+func (tv *BitListView) Bitlist() (*typed.Bitlist, error) {
+	size, err := tv.ValueByteLength()
+	if err != nil {
+		return nil, err
+	}
+	w := codec.NewBytesEncodingWriter(make([]byte, 0, size))
+	if err := tv.Serialize(w); err != nil {
+		return nil, err
+	}
+	return typed.BitlistFromBytes(w.Bytes(), tv.BitLimit)
+}
+
 func (tv *BitListView) ValueByteLength() (uint64, error) {
 	length, err := tv.Length()
 	if err != nil {
@@ -2,6 +2,7 @@ package view
 
 import (
 	"fmt"
+	"github.com/protolambda/ztyp/bitfields/typed"
 	"github.com/protolambda/ztyp/codec"
 	. "github.com/protolambda/ztyp/tree"
 )
@@ -39,6 +40,14 @@ func (td *BitVectorTypeDef) FromBits(bits []bool) (*BitVectorView, error) {
 	return view.(*BitVectorView), nil
 }
 
+// FromBitvector creates a view of the given bitvector, which must have the same length.
+func (td *BitVectorTypeDef) FromBitvector(b *typed.Bitvector) (*BitVectorView, error) {
+	if b.BitLen() != td.BitLength {
+		return nil, fmt.Errorf("bitvector length %d does not match type length %d", b.BitLen(), td.BitLength)
+	}
+	return AsBitVector(td.Deserialize(codec.NewBytesDecodingReader(b.Bytes())))
+}
+
 func (td *BitVectorTypeDef) Length() uint64 {
 	return td.BitLength
 }
@@ -81,16 +90,16 @@ func (td *BitVectorTypeDef) New() *BitVectorView {
 func (td *BitVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	scope := dr.Scope()
 	if td.Size != scope {
-		return nil, fmt.Errorf("expected size %d does not match scope %d", td.Size, scope)
+		return nil, dr.Errorf("expected size %d does not match scope %d", td.Size, scope)
 	}
-	contents := make([]byte, scope, scope)
-	if _, err := dr.Read(contents); err != nil {
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
 		return nil, err
 	}
 	if scope != 0 && td.BitLength&7 != 0 {
 		last := contents[scope-1]
 		if last&byte((uint16(1)<<(td.BitLength&7))-1) != last {
-			return nil, fmt.Errorf("last bitvector byte %d has out of bounds bits set", last)
+			return nil, dr.Errorf("last bitvector byte %d has out of bounds bits set", last)
 		}
 	}
 	bottomNodes, err := BytesIntoNodes(contents)
@@ -184,6 +193,31 @@ func (tv *BitVectorView) ReadonlyIter() BitIter {
 	return bitReadonlyIter(tv.BackingNode, tv.BitLength, tv.depth)
 }
 
+// ReadonlyIterRange iterates over the bits [start, end), without visiting the subtrees of other bits.
+func (tv *BitVectorView) ReadonlyIterRange(start uint64, end uint64) BitIter {
+	if err := checkIterRange(start, end, tv.BitLength); err != nil {
+		return ErrBitIter{err}
+	}
+	return bitRangeIter(tv.BackingNode, tv.depth, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the bits [start, end) in reverse order, starting at end-1.
+func (tv *BitVectorView) ReadonlyIterReverse(start uint64, end uint64) BitIter {
+	if err := checkIterRange(start, end, tv.BitLength); err != nil {
+		return ErrBitIter{err}
+	}
+	return bitRangeIter(tv.BackingNode, tv.depth, start, end, true)
+}
+
+// Bitvector returns the bits as a packed bitvector.
+func (tv *BitVectorView) Bitvector() (*typed.Bitvector, error) {
+	w := codec.NewBytesEncodingWriter(make([]byte, 0, tv.Size))
+	if err := tv.Serialize(w); err != nil {
+		return nil, err
+	}
+	return typed.BitvectorFromBytes(w.Bytes(), tv.BitLength)
+}
+
 func (tv *BitVectorView) ValueByteLength() (uint64, error) {
 	return tv.Size, nil
 }
@@ -0,0 +1,207 @@
+package view
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/conv"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// ByteListTypeDef is a List[byte, N], merkleized like BasicListType(ByteType, N),
+// but accessed as a whole byte slice. Useful for large byte strings, e.g. transactions.
+type ByteListTypeDef struct {
+	ListLimit uint64
+	ComplexTypeBase
+}
+
+func ByteListType(limit uint64) *ByteListTypeDef {
+	return &ByteListTypeDef{
+		ListLimit: limit,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     0,
+			MaxSize:     limit,
+			Size:        0,
+			IsFixedSize: false,
+		},
+	}
+}
+
+func (td *ByteListTypeDef) FromBytes(b []byte) (*ByteListView, error) {
+	length := uint64(len(b))
+	if length > td.ListLimit {
+		return nil, fmt.Errorf("expected no more than %d bytes, got %d", td.ListLimit, length)
+	}
+	if length == 0 {
+		return td.New(), nil
+	}
+	bottomNodes, err := BytesIntoNodes(b)
+	if err != nil {
+		return nil, err
+	}
+	depth := CoverDepth(td.BottomNodeLimit())
+	contentsRootNode, _ := SubtreeFillToContents(bottomNodes, depth)
+	rootNode := &PairNode{LeftChild: contentsRootNode, RightChild: Uint64View(length).Backing()}
+	listView, _ := td.ViewFromBacking(rootNode, nil)
+	return listView.(*ByteListView), nil
+}
+
+func (td *ByteListTypeDef) ElementType() TypeDef {
+	return ByteType
+}
+
+func (td *ByteListTypeDef) Limit() uint64 {
+	return td.ListLimit
+}
+
+func (td *ByteListTypeDef) BottomNodeLimit() uint64 {
+	return (td.ListLimit + 31) / 32
+}
+
+func (td *ByteListTypeDef) DefaultNode() Node {
+	depth := CoverDepth(td.BottomNodeLimit())
+	return &PairNode{LeftChild: &ZeroHashes[depth], RightChild: &ZeroHashes[0]}
+}
+
+func (td *ByteListTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	depth := CoverDepth(td.BottomNodeLimit())
+	return &ByteListView{
+		SubtreeView: SubtreeView{
+			BackedView: BackedView{
+				ViewBase: ViewBase{
+					TypeDef: td,
+				},
+				Hook:        hook,
+				BackingNode: node,
+			},
+			depth: depth + 1, // +1 for length mix-in
+		},
+		ByteListTypeDef: td,
+	}, nil
+}
+
+func (td *ByteListTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *ByteListTypeDef) New() *ByteListView {
+	return td.Default(nil).(*ByteListView)
+}
+
+func (td *ByteListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	scope := dr.Scope()
+	if scope > td.ListLimit {
+		return nil, dr.Errorf("too many bytes, limit %d but got %d", td.ListLimit, scope)
+	}
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
+		return nil, err
+	}
+	return td.FromBytes(contents)
+}
+
+func (td *ByteListTypeDef) String() string {
+	return fmt.Sprintf("ByteList[%d]", td.ListLimit)
+}
+
+type ByteListView struct {
+	SubtreeView
+	*ByteListTypeDef
+}
+
+func AsByteList(v View, err error) (*ByteListView, error) {
+	if err != nil {
+		return nil, err
+	}
+	bv, ok := v.(*ByteListView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a byte list: %v", v)
+	}
+	return bv, nil
+}
+
+func (tv *ByteListView) Length() (uint64, error) {
+	v, err := tv.SubtreeView.BackingNode.Getter(RightGindex)
+	if err != nil {
+		return 0, err
+	}
+	llBytes, ok := v.(*Root)
+	if !ok {
+		return 0, fmt.Errorf("cannot read node %v as list-length", v)
+	}
+	ll := binary.LittleEndian.Uint64(llBytes[:8])
+	if ll > tv.ListLimit {
+		return 0, fmt.Errorf("cannot read list length, length appears to be bigger than limit allows")
+	}
+	return ll, nil
+}
+
+// Bytes returns a copy of the contents.
+func (tv *ByteListView) Bytes() ([]byte, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return nil, err
+	}
+	contentsAnchor, err := tv.BackingNode.Getter(LeftGindex)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, length, length)
+	// one less depth, ignore length mix-in
+	if err := SubtreeIntoBytes(contentsAnchor, tv.depth-1, (length+31)/32, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetBytes replaces the contents. The length must not exceed the list limit.
+func (tv *ByteListView) SetBytes(b []byte) error {
+	v, err := tv.FromBytes(b)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(v.BackingNode)
+}
+
+func (tv *ByteListView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+func (tv *ByteListView) ValueByteLength() (uint64, error) {
+	return tv.Length()
+}
+
+func (tv *ByteListView) Serialize(w *codec.EncodingWriter) error {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return err
+	}
+	return w.Write(contents)
+}
+
+func (tv *ByteListView) MarshalText() ([]byte, error) {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return conv.BytesMarshalText(contents)
+}
+
+func (tv *ByteListView) UnmarshalText(text []byte) error {
+	var contents []byte
+	if err := conv.DynamicBytesUnmarshalText(&contents, text); err != nil {
+		return err
+	}
+	return tv.SetBytes(contents)
+}
+
+func (tv *ByteListView) String() string {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return fmt.Sprintf("invalid %s: %v", tv.ByteListTypeDef.String(), err)
+	}
+	return conv.BytesString(contents)
+}
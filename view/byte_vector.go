@@ -0,0 +1,207 @@
+package view
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/conv"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// ByteVectorTypeDef is a Vector[byte, N], merkleized like BasicVectorType(ByteType, N),
+// but accessed as a whole byte slice. Useful for byte strings longer than 32 bytes, e.g. BLS pubkeys and signatures.
+type ByteVectorTypeDef struct {
+	VectorLength uint64
+	ComplexTypeBase
+}
+
+func ByteVectorType(length uint64) *ByteVectorTypeDef {
+	return &ByteVectorTypeDef{
+		VectorLength: length,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     length,
+			MaxSize:     length,
+			Size:        length,
+			IsFixedSize: true,
+		},
+	}
+}
+
+var Bytes48Type = ByteVectorType(48)
+var Bytes96Type = ByteVectorType(96)
+
+func (td *ByteVectorTypeDef) FromBytes(b []byte) (*ByteVectorView, error) {
+	if uint64(len(b)) != td.VectorLength {
+		return nil, fmt.Errorf("expected %d bytes, got %d", td.VectorLength, len(b))
+	}
+	bottomNodes, err := BytesIntoNodes(b)
+	if err != nil {
+		return nil, err
+	}
+	depth := CoverDepth(td.BottomNodeLength())
+	rootNode, _ := SubtreeFillToContents(bottomNodes, depth)
+	vecView, _ := td.ViewFromBacking(rootNode, nil)
+	return vecView.(*ByteVectorView), nil
+}
+
+func (td *ByteVectorTypeDef) ElementType() TypeDef {
+	return ByteType
+}
+
+func (td *ByteVectorTypeDef) Length() uint64 {
+	return td.VectorLength
+}
+
+func (td *ByteVectorTypeDef) BottomNodeLength() uint64 {
+	return (td.VectorLength + 31) / 32
+}
+
+func (td *ByteVectorTypeDef) DefaultNode() Node {
+	depth := CoverDepth(td.BottomNodeLength())
+	return SubtreeFillToDepth(&ZeroHashes[0], depth)
+}
+
+func (td *ByteVectorTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	depth := CoverDepth(td.BottomNodeLength())
+	return &ByteVectorView{
+		SubtreeView: SubtreeView{
+			BackedView: BackedView{
+				ViewBase: ViewBase{
+					TypeDef: td,
+				},
+				Hook:        hook,
+				BackingNode: node,
+			},
+			depth: depth,
+		},
+		ByteVectorTypeDef: td,
+	}, nil
+}
+
+func (td *ByteVectorTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *ByteVectorTypeDef) New() *ByteVectorView {
+	return td.Default(nil).(*ByteVectorView)
+}
+
+func (td *ByteVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	scope := dr.Scope()
+	if td.Size != scope {
+		return nil, dr.Errorf("expected size %d does not match scope %d", td.Size, scope)
+	}
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
+		return nil, err
+	}
+	return td.FromBytes(contents)
+}
+
+func (td *ByteVectorTypeDef) String() string {
+	return fmt.Sprintf("ByteVector[%d]", td.VectorLength)
+}
+
+type ByteVectorView struct {
+	SubtreeView
+	*ByteVectorTypeDef
+}
+
+func AsByteVector(v View, err error) (*ByteVectorView, error) {
+	if err != nil {
+		return nil, err
+	}
+	bv, ok := v.(*ByteVectorView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a byte vector: %v", v)
+	}
+	return bv, nil
+}
+
+// Bytes returns a copy of the contents.
+func (tv *ByteVectorView) Bytes() ([]byte, error) {
+	out := make([]byte, tv.VectorLength, tv.VectorLength)
+	if err := SubtreeIntoBytes(tv.BackingNode, tv.depth, tv.BottomNodeLength(), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetBytes replaces the contents. The length must match the vector length.
+func (tv *ByteVectorView) SetBytes(b []byte) error {
+	v, err := tv.FromBytes(b)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(v.BackingNode)
+}
+
+func (tv *ByteVectorView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+func (tv *ByteVectorView) ValueByteLength() (uint64, error) {
+	return tv.Size, nil
+}
+
+func (tv *ByteVectorView) Serialize(w *codec.EncodingWriter) error {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return err
+	}
+	return w.Write(contents)
+}
+
+func (tv *ByteVectorView) MarshalText() ([]byte, error) {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return conv.BytesMarshalText(contents)
+}
+
+func (tv *ByteVectorView) UnmarshalText(text []byte) error {
+	contents := make([]byte, tv.VectorLength, tv.VectorLength)
+	if err := conv.FixedBytesUnmarshalText(contents, text); err != nil {
+		return err
+	}
+	return tv.SetBytes(contents)
+}
+
+func (tv *ByteVectorView) String() string {
+	contents, err := tv.Bytes()
+	if err != nil {
+		return fmt.Sprintf("invalid %s: %v", tv.ByteVectorTypeDef.String(), err)
+	}
+	return conv.BytesString(contents)
+}
+
+func AsBytes48(v View, err error) ([48]byte, error) {
+	const byteLen = 48
+	data, err := AsByteVector(v, err)
+	if err != nil {
+		return [byteLen]byte{}, err
+	}
+	if data.VectorLength != byteLen {
+		return [byteLen]byte{}, fmt.Errorf("expected %d byte long byte vector, got %d byte long", byteLen, data.VectorLength)
+	}
+	var out [byteLen]byte
+	err = SubtreeIntoBytes(data.BackingNode, data.depth, data.BottomNodeLength(), out[:])
+	return out, err
+}
+
+func AsBytes96(v View, err error) ([96]byte, error) {
+	const byteLen = 96
+	data, err := AsByteVector(v, err)
+	if err != nil {
+		return [byteLen]byte{}, err
+	}
+	if data.VectorLength != byteLen {
+		return [byteLen]byte{}, fmt.Errorf("expected %d byte long byte vector, got %d byte long", byteLen, data.VectorLength)
+	}
+	var out [byteLen]byte
+	err = SubtreeIntoBytes(data.BackingNode, data.depth, data.BottomNodeLength(), out[:])
+	return out, err
+}
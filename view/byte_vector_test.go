@@ -0,0 +1,112 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestByteVectorBytes(t *testing.T) {
+	hFn := tree.GetHashFn()
+	data := make([]byte, 48)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	v := Bytes48Type.New()
+	if err := v.SetBytes(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetBytes(data[:47]); err == nil {
+		t.Fatal("expected error for wrong length")
+	}
+	out, err := v.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatalf("got %x, expected %x", out, data)
+	}
+	arr, err := AsBytes48(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(arr[:], data) {
+		t.Fatalf("got %x, expected %x", arr, data)
+	}
+	if _, err := AsBytes96(v, nil); err == nil {
+		t.Fatal("expected error for wrong length")
+	}
+	// merkleized the same as a vector of bytes
+	elems := make([]BasicView, len(data))
+	for i, b := range data {
+		elems[i] = ByteView(b)
+	}
+	basic, err := BasicVectorType(ByteType, 48).FromElements(elems...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.HashTreeRoot(hFn) != basic.HashTreeRoot(hFn) {
+		t.Fatal("root does not match basic vector of bytes")
+	}
+	enc, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := Bytes48Type.New()
+	if err := json.Unmarshal(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.HashTreeRoot(hFn) != v.HashTreeRoot(hFn) {
+		t.Fatalf("decoded %s does not match", enc)
+	}
+}
+
+func TestByteListBytes(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := ByteListType(100)
+	v := td.New()
+	for _, n := range []int{70, 3, 0, 100} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i + n)
+		}
+		if err := v.SetBytes(data); err != nil {
+			t.Fatal(err)
+		}
+		out, err := v.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, data) {
+			t.Fatalf("got %x, expected %x", out, data)
+		}
+		elems := make([]BasicView, len(data))
+		for i, b := range data {
+			elems[i] = ByteView(b)
+		}
+		basic := BasicListType(ByteType, 100).New()
+		for _, el := range elems {
+			if err := basic.Append(el); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if v.HashTreeRoot(hFn) != basic.HashTreeRoot(hFn) {
+			t.Fatalf("length %d: root does not match basic list of bytes", n)
+		}
+		enc, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := td.New()
+		if err := json.Unmarshal(enc, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.HashTreeRoot(hFn) != v.HashTreeRoot(hFn) {
+			t.Fatalf("decoded %s does not match", enc)
+		}
+	}
+	if err := v.SetBytes(make([]byte, 101)); err == nil {
+		t.Fatal("expected error for exceeding limit")
+	}
+}
@@ -0,0 +1,39 @@
+package view
+
+import "github.com/protolambda/ztyp/codec"
+
+// SerializeToBytes serializes v to a new byte slice, allocated once with the value byte length of v.
+func SerializeToBytes(v View) ([]byte, error) {
+	size, err := v.ValueByteLength()
+	if err != nil {
+		return nil, err
+	}
+	return codec.EncodeInto(nil, size, v.Serialize)
+}
+
+// SerializeToPool serializes v to a buffer from the pool, see codec.EncodeToPool.
+// The caller can give the buffer back to the pool when done with it.
+func SerializeToPool(v View, pool codec.BufferPool) ([]byte, error) {
+	size, err := v.ValueByteLength()
+	if err != nil {
+		return nil, err
+	}
+	return codec.EncodeToPool(sizedView{View: v, size: size}, pool)
+}
+
+// sizedView adapts a view with a known value byte length to a codec.Serializable.
+type sizedView struct {
+	View
+	size uint64
+}
+
+func (v sizedView) ByteLength() uint64 {
+	return v.size
+}
+
+func (v sizedView) FixedLength() uint64 {
+	if t := v.Type(); t.IsFixedByteLength() {
+		return t.TypeByteLength()
+	}
+	return 0
+}
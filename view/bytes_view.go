@@ -0,0 +1,450 @@
+package view
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+)
+
+// BytesView is a read-only view over the SSZ encoding of a value.
+// Elements are navigated with offsets, without decoding the rest of the value:
+// only the sizes and offsets on the way to an element are checked, everything else is left untouched.
+// Sub-views share the underlying bytes, which must not be modified.
+type BytesView struct {
+	typ  TypeDef
+	data []byte
+	// position of data in the outermost encoding, for errors
+	pos  uint64
+	path []codec.PathElem
+}
+
+// NewBytesView checks that the size of the data fits the type, and wraps it in a BytesView.
+func NewBytesView(typ TypeDef, data []byte) (*BytesView, error) {
+	v := &BytesView{typ: typ, data: data}
+	if err := v.checkSize(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *BytesView) Type() TypeDef {
+	return v.typ
+}
+
+// Bytes returns the encoding of the value, without copying.
+func (v *BytesView) Bytes() []byte {
+	return v.data
+}
+
+// Position returns the byte position of the value in the outermost encoding.
+func (v *BytesView) Position() uint64 {
+	return v.pos
+}
+
+// View decodes the value into a regular tree-backed view.
+func (v *BytesView) View() (View, error) {
+	dr := codec.NewBytesDecodingReader(v.data)
+	out, err := v.typ.Deserialize(dr)
+	if err != nil {
+		return nil, v.wrap(err)
+	}
+	return out, nil
+}
+
+// Uint64 decodes the value as uint64. It may be a uint of 1 to 8 bytes.
+func (v *BytesView) Uint64() (uint64, error) {
+	if _, ok := v.typ.(UintMeta); !ok || len(v.data) > 8 {
+		return 0, v.errorf(0, "cannot read %s as uint64", v.typ.String())
+	}
+	var tmp [8]byte
+	copy(tmp[:], v.data)
+	return binary.LittleEndian.Uint64(tmp[:]), nil
+}
+
+// Length returns the number of elements, or number of fields for containers.
+// For bitfields this is the number of bits.
+func (v *BytesView) Length() (uint64, error) {
+	size := uint64(len(v.data))
+	switch t := v.typ.(type) {
+	case *ContainerTypeDef:
+		return uint64(len(t.Fields)), nil
+	case *StableContainerTypeDef:
+		return uint64(len(t.Fields)), nil
+	case *ProfileTypeDef:
+		return uint64(len(t.Fields)), nil
+	case *BasicVectorTypeDef:
+		return t.VectorLength, nil
+	case *ComplexVectorTypeDef:
+		return t.VectorLength, nil
+	case *ByteVectorTypeDef:
+		return t.VectorLength, nil
+	case *ByteListTypeDef:
+		return size, nil
+	case *BitVectorTypeDef:
+		return t.BitLength, nil
+	case *BasicListTypeDef:
+		return size / t.ElemType.TypeByteLength(), nil
+	case *BasicProgressiveListTypeDef:
+		return size / t.ElemType.TypeByteLength(), nil
+	case *ComplexListTypeDef:
+		return v.seriesLength(t.ElemType)
+	case *ComplexProgressiveListTypeDef:
+		return v.seriesLength(t.ElemType)
+	case *BitListTypeDef:
+		if err := bitfields.BitlistCheck(v.data, t.BitLimit); err != nil {
+			return 0, v.errorf(0, "%v", err)
+		}
+		return bitfields.BitlistLen(v.data), nil
+	default:
+		return 0, v.errorf(0, "%s has no elements", v.typ.String())
+	}
+}
+
+// seriesLength returns the length of a list of non-basic elements
+func (v *BytesView) seriesLength(elemType TypeDef) (uint64, error) {
+	size := uint64(len(v.data))
+	if elemType.IsFixedByteLength() {
+		return size / elemType.TypeByteLength(), nil
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	first, err := v.offset(0)
+	if err != nil {
+		return 0, err
+	}
+	if first == 0 || first%OffsetByteLength != 0 || first > size {
+		return 0, v.errorf(0, "invalid first offset %d", first)
+	}
+	return first / OffsetByteLength, nil
+}
+
+// Field returns the container field with the given name.
+// Fields of StableContainers and Profiles that are not present are an error.
+func (v *BytesView) Field(name string) (*BytesView, error) {
+	switch t := v.typ.(type) {
+	case *ContainerTypeDef:
+		if i, ok := t.FieldIndex(name); ok {
+			return v.Get(i)
+		}
+	case *StableContainerTypeDef:
+		for i, f := range t.Fields {
+			if f.Name == name {
+				return v.Get(uint64(i))
+			}
+		}
+	case *ProfileTypeDef:
+		for i, f := range t.Fields {
+			if f.Name == name {
+				return v.Get(uint64(i))
+			}
+		}
+	default:
+		return nil, v.errorf(0, "cannot get field %q of non-container type %s", name, v.typ.String())
+	}
+	return nil, v.errorf(0, "%s has no field %q", v.typ.String(), name)
+}
+
+// Get returns the field or element at the given index.
+// For unions, the index must match the selector, and the value of the union is returned.
+// The value of an optional is at index 0, if it is not None.
+func (v *BytesView) Get(i uint64) (*BytesView, error) {
+	size := uint64(len(v.data))
+	switch t := v.typ.(type) {
+	case *ContainerTypeDef:
+		return v.getField(t.Fields, nil, 0, i)
+	case *StableContainerTypeDef:
+		return v.getStableField(t, i)
+	case *ProfileTypeDef:
+		return v.getProfileField(t, i)
+	case *BasicVectorTypeDef:
+		if i >= t.VectorLength {
+			return nil, v.errorf(0, "index %d out of range, vector length is %d", i, t.VectorLength)
+		}
+		elemSize := t.ElemType.TypeByteLength()
+		return v.sub(t.ElemType, i*elemSize, (i+1)*elemSize, PathIndex(i))
+	case *ByteVectorTypeDef:
+		if i >= t.VectorLength {
+			return nil, v.errorf(0, "index %d out of range, vector length is %d", i, t.VectorLength)
+		}
+		return v.sub(ByteType, i, i+1, PathIndex(i))
+	case *ByteListTypeDef:
+		return v.getBasicListElem(ByteType, i)
+	case *BasicListTypeDef:
+		return v.getBasicListElem(t.ElemType, i)
+	case *BasicProgressiveListTypeDef:
+		return v.getBasicListElem(t.ElemType, i)
+	case *ComplexVectorTypeDef:
+		if i >= t.VectorLength {
+			return nil, v.errorf(0, "index %d out of range, vector length is %d", i, t.VectorLength)
+		}
+		return v.getElem(t.ElemType, i, t.VectorLength)
+	case *ComplexListTypeDef:
+		return v.getListElem(t.ElemType, i)
+	case *ComplexProgressiveListTypeDef:
+		return v.getListElem(t.ElemType, i)
+	case *UnionTypeDef:
+		if size == 0 {
+			return nil, v.errorf(0, "missing union selector")
+		}
+		selector := uint64(v.data[0])
+		if selector != i {
+			return nil, v.errorf(0, "union selector is %d, not %d", selector, i)
+		}
+		if selector >= uint64(len(t.Options)) || t.Options[selector] == nil {
+			return nil, v.errorf(0, "union selector %d has no value", selector)
+		}
+		return v.sub(t.Options[selector], 1, size, PathIndex(i))
+	case *OptionalTypeDef:
+		if i != 0 {
+			return nil, v.errorf(0, "optional has only index 0, not %d", i)
+		}
+		if size == 0 {
+			return nil, v.errorf(0, "optional is None")
+		}
+		if v.data[0] != 1 {
+			return nil, v.errorf(0, "optional value must be prefixed with 0x01, got %d", v.data[0])
+		}
+		return v.sub(t.ElemType, 1, size, PathIndex(0))
+	default:
+		return nil, v.errorf(0, "cannot get element %d of %s", i, v.typ.String())
+	}
+}
+
+// Path navigates the fields and elements of the given path, see GetPath for tree-backed views.
+func (v *BytesView) Path(path ...codec.PathElem) (*BytesView, error) {
+	out := v
+	for _, p := range path {
+		var err error
+		if p.Field != "" {
+			out, err = out.Field(p.Field)
+		} else {
+			out, err = out.Get(p.Index)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (v *BytesView) getBasicListElem(elemType BasicTypeDef, i uint64) (*BytesView, error) {
+	elemSize := elemType.TypeByteLength()
+	if length := uint64(len(v.data)) / elemSize; i >= length {
+		return nil, v.errorf(0, "index %d out of range, list length is %d", i, length)
+	}
+	return v.sub(elemType, i*elemSize, (i+1)*elemSize, PathIndex(i))
+}
+
+func (v *BytesView) getListElem(elemType TypeDef, i uint64) (*BytesView, error) {
+	length, err := v.seriesLength(elemType)
+	if err != nil {
+		return nil, err
+	}
+	if i >= length {
+		return nil, v.errorf(0, "index %d out of range, list length is %d", i, length)
+	}
+	return v.getElem(elemType, i, length)
+}
+
+// getElem gets the element at index i of a series of the given length, fixed or variable size elements.
+func (v *BytesView) getElem(elemType TypeDef, i uint64, length uint64) (*BytesView, error) {
+	if elemType.IsFixedByteLength() {
+		elemSize := elemType.TypeByteLength()
+		return v.sub(elemType, i*elemSize, (i+1)*elemSize, PathIndex(i))
+	}
+	fixedPart := length * OffsetByteLength
+	start, err := v.offset(i * OffsetByteLength)
+	if err != nil {
+		return nil, err
+	}
+	end := uint64(len(v.data))
+	if i+1 < length {
+		if end, err = v.offset((i + 1) * OffsetByteLength); err != nil {
+			return nil, err
+		}
+	}
+	if start < fixedPart {
+		return nil, v.errorf(i*OffsetByteLength, "offset %d points into fixed part of %d bytes", start, fixedPart)
+	}
+	return v.sub(elemType, start, end, PathIndex(i))
+}
+
+// getField gets field i of fields that are encoded like a container, from byte start on.
+// Only the present fields are encoded, all fields are present if present is nil.
+func (v *BytesView) getField(fields []FieldDef, present []bool, start uint64, i uint64) (*BytesView, error) {
+	if i >= uint64(len(fields)) {
+		return nil, v.errorf(0, "field %d out of range, %s has %d fields", i, v.typ.String(), len(fields))
+	}
+	f := fields[i]
+	if present != nil && !present[i] {
+		return nil, v.errorf(0, "field %q of %s is not present", f.Name, v.typ.String())
+	}
+	// find the position of the field (or its offset) in the fixed part,
+	// the position of the offset of the next variable size field, if any, and the end of the fixed part
+	fixedPos, fixedEnd := start, start
+	nextPos, hasNext := uint64(0), false
+	for j, g := range fields {
+		if present != nil && !present[j] {
+			continue
+		}
+		if uint64(j) == i {
+			fixedPos = fixedEnd
+		} else if uint64(j) > i && !hasNext && !g.Type.IsFixedByteLength() {
+			nextPos, hasNext = fixedEnd, true
+		}
+		if g.Type.IsFixedByteLength() {
+			fixedEnd += g.Type.TypeByteLength()
+		} else {
+			fixedEnd += OffsetByteLength
+		}
+	}
+	elem := codec.PathElem{Field: f.Name}
+	if f.Type.IsFixedByteLength() {
+		return v.sub(f.Type, fixedPos, fixedPos+f.Type.TypeByteLength(), elem)
+	}
+	// offsets are relative to the start of the fields
+	offset, err := v.offset(fixedPos)
+	if err != nil {
+		return nil, err
+	}
+	end := uint64(len(v.data))
+	if hasNext {
+		next, err := v.offset(nextPos)
+		if err != nil {
+			return nil, err
+		}
+		end = start + next
+	}
+	if fixedPartSize := fixedEnd - start; offset < fixedPartSize {
+		return nil, v.errorf(fixedPos, "offset %d points into fixed part of %d bytes", offset, fixedPartSize)
+	}
+	return v.sub(f.Type, start+offset, end, elem)
+}
+
+// getStableField gets field i of a StableContainer, if it is active.
+func (v *BytesView) getStableField(t *StableContainerTypeDef, i uint64) (*BytesView, error) {
+	active, err := v.activeBits(t.ActiveFieldsType)
+	if err != nil {
+		return nil, err
+	}
+	return v.getField(t.Fields, active[:len(t.Fields)], t.ActiveFieldsType.TypeByteLength(), i)
+}
+
+// getProfileField gets field i of a Profile, if it is required, or an optional field that is present.
+func (v *BytesView) getProfileField(t *ProfileTypeDef, i uint64) (*BytesView, error) {
+	start := uint64(0)
+	var optionalActive []bool
+	if t.OptionalFieldsType != nil {
+		var err error
+		if optionalActive, err = v.activeBits(t.OptionalFieldsType); err != nil {
+			return nil, err
+		}
+		start = t.OptionalFieldsType.TypeByteLength()
+	}
+	fields := make([]FieldDef, len(t.Fields), len(t.Fields))
+	present := make([]bool, len(t.Fields), len(t.Fields))
+	j := 0
+	for k, f := range t.Fields {
+		fields[k] = FieldDef{Name: f.Name, Type: f.Type}
+		if f.Optional {
+			present[k] = optionalActive[j]
+			j++
+		} else {
+			present[k] = true
+		}
+	}
+	return v.getField(fields, present, start, i)
+}
+
+// activeBits reads the bitvector at the start of the data.
+func (v *BytesView) activeBits(td *BitVectorTypeDef) ([]bool, error) {
+	size := td.TypeByteLength()
+	if uint64(len(v.data)) < size {
+		return nil, v.errorf(0, "cannot read bitvector of %d bytes, only %d bytes", size, len(v.data))
+	}
+	bits := v.data[:size]
+	if err := bitfields.BitvectorCheck(bits, td.BitLength); err != nil {
+		return nil, v.errorf(0, "%v", err)
+	}
+	out := make([]bool, td.BitLength, td.BitLength)
+	for i := range out {
+		out[i] = bitfields.GetBit(bits, uint64(i))
+	}
+	return out, nil
+}
+
+// offset reads the offset at the given position
+func (v *BytesView) offset(at uint64) (uint64, error) {
+	if at+OffsetByteLength > uint64(len(v.data)) {
+		return 0, v.errorf(at, "cannot read offset, only %d bytes", len(v.data))
+	}
+	return uint64(binary.LittleEndian.Uint32(v.data[at : at+OffsetByteLength])), nil
+}
+
+func (v *BytesView) sub(typ TypeDef, start uint64, end uint64, elem codec.PathElem) (*BytesView, error) {
+	if start > end || end > uint64(len(v.data)) {
+		return nil, v.errorf(0, "invalid span %d to %d of %d bytes", start, end, len(v.data))
+	}
+	path := make([]codec.PathElem, len(v.path)+1, len(v.path)+1)
+	copy(path, v.path)
+	path[len(v.path)] = elem
+	out := &BytesView{typ: typ, data: v.data[start:end], pos: v.pos + start, path: path}
+	if err := out.checkSize(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (v *BytesView) checkSize() error {
+	size := uint64(len(v.data))
+	if v.typ.IsFixedByteLength() {
+		if expected := v.typ.TypeByteLength(); size != expected {
+			return v.errorf(0, "%s: expected %d bytes, got %d", v.typ.String(), expected, size)
+		}
+		return nil
+	}
+	if min, max := v.typ.MinByteLength(), v.typ.MaxByteLength(); size < min || size > max {
+		return v.errorf(0, "%s: size of %d bytes is out of range, expected %d to %d bytes", v.typ.String(), size, min, max)
+	}
+	switch t := v.typ.(type) {
+	case *BasicListTypeDef:
+		if elemSize := t.ElemType.TypeByteLength(); size%elemSize != 0 {
+			return v.errorf(0, "%s: size %d does not align to element size %d", t.String(), size, elemSize)
+		}
+	case *BasicProgressiveListTypeDef:
+		if elemSize := t.ElemType.TypeByteLength(); size%elemSize != 0 {
+			return v.errorf(0, "%s: size %d does not align to element size %d", t.String(), size, elemSize)
+		}
+	case *ComplexListTypeDef:
+		if t.ElemType.IsFixedByteLength() {
+			if elemSize := t.ElemType.TypeByteLength(); size%elemSize != 0 {
+				return v.errorf(0, "%s: size %d does not align to element size %d", t.String(), size, elemSize)
+			}
+		}
+	case *ComplexProgressiveListTypeDef:
+		if t.ElemType.IsFixedByteLength() {
+			if elemSize := t.ElemType.TypeByteLength(); size%elemSize != 0 {
+				return v.errorf(0, "%s: size %d does not align to element size %d", t.String(), size, elemSize)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *BytesView) errorf(at uint64, format string, a ...interface{}) error {
+	return &codec.DecodeError{Offset: v.pos + at, Path: v.path, Err: fmt.Errorf(format, a...)}
+}
+
+func (v *BytesView) wrap(err error) error {
+	de, ok := err.(*codec.DecodeError)
+	if !ok {
+		return v.errorf(0, "%w", err)
+	}
+	path := make([]codec.PathElem, 0, len(v.path)+len(de.Path))
+	path = append(path, v.path...)
+	de.Path = append(path, de.Path...)
+	de.Offset += v.pos
+	return de
+}
@@ -0,0 +1,234 @@
+package view
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestBytesView(t *testing.T) {
+	data, err := hex.DecodeString("08000000" + "15000000" +
+		"adde0700000011010002000300" +
+		"efbe0700000022040005000600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv, err := NewBytesView(ListBType, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length, err := bv.Length(); err != nil || length != 2 {
+		t.Fatalf("expected length 2, got %d: %v", length, err)
+	}
+	path := []codec.PathElem{PathIndex(1), PathField("B"), PathIndex(2)}
+	elem, err := bv.Path(path...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err := elem.Uint64(); err != nil || x != 6 {
+		t.Fatalf("expected 6, got %d: %v", x, err)
+	}
+	if elem.Position() != 32 {
+		t.Errorf("expected element at byte 32, got %d", elem.Position())
+	}
+
+	// same path on the decoded tree-backed view
+	full, err := bv.View()
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeElem, err := GetPath(full, path...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if treeElem.(Uint16View) != 6 {
+		t.Fatalf("expected 6, got %d", treeElem)
+	}
+	sub, err := bv.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subView, err := sub.View()
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeSub, err := GetPath(full, PathIndex(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hFn := tree.GetHashFn()
+	if subView.HashTreeRoot(hFn) != treeSub.HashTreeRoot(hFn) {
+		t.Fatal("element decoded from bytes view does not match tree-backed element")
+	}
+}
+
+func TestBytesViewInvalidOffset(t *testing.T) {
+	// the offset of field B of the second element points into the fixed part
+	data, err := hex.DecodeString("08000000" + "15000000" +
+		"adde0700000011010002000300" +
+		"efbe0300000022040005000600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv, err := NewBytesView(ListBType, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// untouched parts are not checked
+	if _, err := bv.Path(PathIndex(0), PathField("B"), PathIndex(1)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = bv.Path(PathIndex(1), PathField("B"))
+	var decErr *codec.DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if decErr.Offset != 23 {
+		t.Errorf("expected error at byte 23, got %d: %v", decErr.Offset, err)
+	}
+	if p := decErr.PathString(); p != "[1]" {
+		t.Errorf("unexpected error path %q: %v", p, err)
+	}
+}
+
+func TestBytesViewOptional(t *testing.T) {
+	td := ContainerType("WithOptional", []FieldDef{
+		{Name: "a", Type: Uint8Type},
+		{Name: "b", Type: OptionalType(VarTestStructType)},
+	})
+	some, err := hex.DecodeString("07" + "05000000" + "01" + "cdab" + "07000000" + "ff" + "0100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv, err := NewBytesView(td, some)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem, err := bv.Path(PathField("b"), PathIndex(0), PathField("B"), PathIndex(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err := elem.Uint64(); err != nil || x != 1 {
+		t.Fatalf("expected 1, got %d: %v", x, err)
+	}
+	if elem.Position() != 13 {
+		t.Errorf("expected element at byte 13, got %d", elem.Position())
+	}
+	if _, err := bv.Path(PathField("b"), PathIndex(1)); err == nil {
+		t.Fatal("expected error for index other than 0")
+	}
+	none, err := hex.DecodeString("07" + "05000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv, err = NewBytesView(td, none)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bv.Path(PathField("b"), PathIndex(0)); err == nil {
+		t.Fatal("expected error for None")
+	}
+}
+
+func TestBytesViewStableContainer(t *testing.T) {
+	// the EIP-7495 examples
+	cases := []struct {
+		typ    TypeDef
+		hex    string
+		values map[string]uint64
+		absent []string
+	}{
+		{ShapeType, "03420001", map[string]uint64{"side": 0x42, "color": 1}, []string{"radius"}},
+		{ShapeType, "06014200", map[string]uint64{"color": 1, "radius": 0x42}, []string{"side"}},
+		{SquareType, "420001", map[string]uint64{"side": 0x42, "color": 1}, nil},
+		{CircleType, "01014200", map[string]uint64{"color": 1, "radius": 0x42}, nil},
+		{CircleType, "0001", map[string]uint64{"color": 1}, []string{"radius"}},
+	}
+	for _, c := range cases {
+		t.Run(c.typ.String()+"_"+c.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(c.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bv, err := NewBytesView(c.typ, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, expected := range c.values {
+				field, err := bv.Field(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if x, err := field.Uint64(); err != nil || x != expected {
+					t.Fatalf("field %s: expected %d, got %d: %v", name, expected, x, err)
+				}
+			}
+			for _, name := range c.absent {
+				if _, err := bv.Field(name); err == nil {
+					t.Fatalf("expected error for absent field %s", name)
+				}
+			}
+		})
+	}
+
+	// variable size fields, compared with the tree-backed view
+	varType := StableContainerType("VarShape", 8, []FieldDef{
+		{"a", Uint8Type},
+		{"b", BasicListType(Uint16Type, 4)},
+		{"c", Uint16Type},
+		{"d", BasicListType(Uint8Type, 4)},
+	})
+	b, err := BasicListType(Uint16Type, 4).FromElements(Uint16View(1), Uint16View(2), Uint16View(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := BasicListType(Uint8Type, 4).FromBytes([]byte{4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := varType.FromFields(nil, b, Uint16View(6), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := SerializeToBytes(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv, err := NewBytesView(varType, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := bv.Length(); err != nil || n != 4 {
+		t.Fatalf("expected 4 fields, got %d: %v", n, err)
+	}
+	if _, err := bv.Get(0); err == nil {
+		t.Fatal("expected error for absent field a")
+	}
+	hFn := tree.GetHashFn()
+	for _, name := range []string{"b", "c", "d"} {
+		field, err := bv.Field(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fieldView, err := field.View()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := GetPath(v, PathField(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fieldView.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+			t.Fatalf("field %s does not match tree-backed field", name)
+		}
+	}
+	elem, err := bv.Path(PathField("d"), PathIndex(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err := elem.Uint64(); err != nil || x != 5 {
+		t.Fatalf("expected 5, got %d: %v", x, err)
+	}
+}
@@ -88,83 +88,14 @@ func (td *ComplexListTypeDef) New() *ComplexListView {
 }
 
 func (td *ComplexListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
-	scope := dr.Scope()
-	if scope == 0 {
-		return td.New(), nil
+	elements, err := deserializeComplexListElems(td.ElemType, td.ListLimit, dr)
+	if err != nil {
+		return nil, err
 	}
-	if td.ElemType.IsFixedByteLength() {
-		elemSize := td.ElemType.TypeByteLength()
-		length := scope / elemSize
-		if length > td.ListLimit {
-			return nil, fmt.Errorf("too many items, limit %d but got %d", td.ListLimit, length)
-		}
-		if expected := length * elemSize; expected != scope {
-			return nil, fmt.Errorf("scope %d does not align to elem size %d", scope, elemSize)
-		}
-		elements := make([]View, length, length)
-		for i := uint64(0); i < length; i++ {
-			sub, err := dr.SubScope(elemSize)
-			if err != nil {
-				return nil, err
-			}
-			el, err := td.ElemType.Deserialize(sub)
-			if err != nil {
-				return nil, err
-			}
-			elements[i] = el
-		}
-		return td.FromElements(elements...)
-	} else {
-		firstOffset, err := dr.ReadOffset()
-		if err != nil {
-			return nil, err
-		}
-		if firstOffset%OffsetByteLength != 0 {
-			return nil, fmt.Errorf("first offset %d does not align to offset length %d", firstOffset, OffsetByteLength)
-		}
-		length := uint64(firstOffset) / OffsetByteLength
-		if length > td.ListLimit {
-			return nil, fmt.Errorf("too many items, limit %d but got %d", td.ListLimit, length)
-		}
-		offsets := make([]uint32, length, length)
-		offsets[0] = firstOffset
-		prevOffset := firstOffset
-		for i := uint64(1); i < length; i++ {
-			offset, err := dr.ReadOffset()
-			if err != nil {
-				return nil, err
-			}
-			if offset < prevOffset {
-				return nil, fmt.Errorf("offset %d for element %d is smaller than previous offset %d", offset, i, prevOffset)
-			}
-			offsets[i] = offset
-			prevOffset = offset
-		}
-		elements := make([]View, length, length)
-		lastIndex := uint32(len(elements) - 1)
-		for i := uint32(0); i < lastIndex; i++ {
-			size := offsets[i+1] - offsets[i]
-			sub, err := dr.SubScope(uint64(size))
-			if err != nil {
-				return nil, err
-			}
-			el, err := td.ElemType.Deserialize(sub)
-			if err != nil {
-				return nil, err
-			}
-			elements[i] = el
-		}
-		sub, err := dr.SubScope(scope - uint64(offsets[lastIndex]))
-		if err != nil {
-			return nil, err
-		}
-		el, err := td.ElemType.Deserialize(sub)
-		if err != nil {
-			return nil, err
-		}
-		elements[lastIndex] = el
-		return td.FromElements(elements...)
+	if len(elements) == 0 {
+		return td.New(), nil
 	}
+	return td.FromElements(elements...)
 }
 
 func (td *ComplexListTypeDef) String() string {
@@ -354,6 +285,44 @@ func (tv *ComplexListView) ReadonlyIter() ElemIter {
 	return elemReadonlyIter(node, length, tv.depth-1, tv.ElemType)
 }
 
+// ReadonlyIterRange iterates over the elements [start, end), without visiting the subtrees of other elements.
+// Like ReadonlyIter, the elements are not bound to the list, and may share the same view instance between steps.
+func (tv *ComplexListView) ReadonlyIterRange(start uint64, end uint64) ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrElemIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	// ignore length mixin in stack
+	return elemRangeIter(node, tv.depth-1, tv.ElemType, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the elements [start, end) in reverse order, starting at end-1.
+// Like ReadonlyIter, the elements are not bound to the list, and may share the same view instance between steps.
+func (tv *ComplexListView) ReadonlyIterReverse(start uint64, end uint64) ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	if err := checkIterRange(start, end, length); err != nil {
+		return ErrElemIter{err}
+	}
+	// get contents subtree, to traverse with the stack
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	// ignore length mixin in stack
+	return elemRangeIter(node, tv.depth-1, tv.ElemType, start, end, true)
+}
+
 func (tv *ComplexListView) ValueByteLength() (uint64, error) {
 	length, err := tv.Length()
 	if err != nil {
@@ -393,3 +362,87 @@ func (tv *ComplexListView) Serialize(w *codec.EncodingWriter) error {
 		return serializeComplexVarElemSeries(length, tv.ReadonlyIter, w)
 	}
 }
+
+// deserializeComplexListElems decodes the elements of a list of non-basic elements, with no more than limit elements.
+func deserializeComplexListElems(elemType TypeDef, limit uint64, dr *codec.DecodingReader) ([]View, error) {
+	scope := dr.Scope()
+	if scope == 0 {
+		return nil, nil
+	}
+	if elemType.IsFixedByteLength() {
+		elemSize := elemType.TypeByteLength()
+		length := scope / elemSize
+		if length > limit {
+			return nil, dr.Errorf("too many items, limit %d but got %d", limit, length)
+		}
+		if expected := length * elemSize; expected != scope {
+			return nil, dr.Errorf("scope %d does not align to elem size %d", scope, elemSize)
+		}
+		elements := make([]View, length, length)
+		for i := uint64(0); i < length; i++ {
+			sub, err := dr.SubScope(elemSize)
+			if err != nil {
+				return nil, err
+			}
+			el, err := elemType.Deserialize(sub)
+			if err != nil {
+				return nil, dr.WrapIndex(uint64(i), err)
+			}
+			elements[i] = el
+		}
+		return elements, nil
+	} else {
+		firstOffset, err := dr.ReadOffset()
+		if err != nil {
+			return nil, err
+		}
+		if firstOffset%OffsetByteLength != 0 {
+			return nil, dr.Errorf("first offset %d does not align to offset length %d", firstOffset, OffsetByteLength)
+		}
+		if firstOffset == 0 || uint64(firstOffset) > scope {
+			return nil, dr.Errorf("first offset %d is out of range for scope %d", firstOffset, scope)
+		}
+		length := uint64(firstOffset) / OffsetByteLength
+		if length > limit {
+			return nil, dr.Errorf("too many items, limit %d but got %d", limit, length)
+		}
+		offsets := make([]uint32, length, length)
+		offsets[0] = firstOffset
+		prevOffset := firstOffset
+		for i := uint64(1); i < length; i++ {
+			offset, err := dr.ReadOffset()
+			if err != nil {
+				return nil, err
+			}
+			if offset < prevOffset {
+				return nil, dr.Errorf("offset %d for element %d is smaller than previous offset %d", offset, i, prevOffset)
+			}
+			offsets[i] = offset
+			prevOffset = offset
+		}
+		elements := make([]View, length, length)
+		lastIndex := uint32(len(elements) - 1)
+		for i := uint32(0); i < lastIndex; i++ {
+			size := offsets[i+1] - offsets[i]
+			sub, err := dr.SubScope(uint64(size))
+			if err != nil {
+				return nil, err
+			}
+			el, err := elemType.Deserialize(sub)
+			if err != nil {
+				return nil, dr.WrapIndex(uint64(i), err)
+			}
+			elements[i] = el
+		}
+		sub, err := dr.SubScope(scope - uint64(offsets[lastIndex]))
+		if err != nil {
+			return nil, err
+		}
+		el, err := elemType.Deserialize(sub)
+		if err != nil {
+			return nil, dr.WrapIndex(uint64(lastIndex), err)
+		}
+		elements[lastIndex] = el
+		return elements, nil
+	}
+}
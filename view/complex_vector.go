@@ -99,7 +99,7 @@ func (td *ComplexVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, err
 	if td.IsFixedSize {
 		elemSize := td.ElemType.TypeByteLength()
 		if td.Size != scope {
-			return nil, fmt.Errorf("expected size %d does not match scope %d", td.Size, scope)
+			return nil, dr.Errorf("expected size %d does not match scope %d", td.Size, scope)
 		}
 		elements := make([]View, td.VectorLength, td.VectorLength)
 		for i := uint64(0); i < td.VectorLength; i++ {
@@ -109,7 +109,7 @@ func (td *ComplexVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, err
 			}
 			el, err := td.ElemType.Deserialize(sub)
 			if err != nil {
-				return nil, err
+				return nil, dr.WrapIndex(uint64(i), err)
 			}
 			elements[i] = el
 		}
@@ -122,8 +122,11 @@ func (td *ComplexVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, err
 			if err != nil {
 				return nil, err
 			}
+			if i == 0 && uint64(offset) != td.VectorLength*OffsetByteLength {
+				return nil, dr.Errorf("first offset %d does not match offsets size %d", offset, td.VectorLength*OffsetByteLength)
+			}
 			if offset < prevOffset {
-				return nil, fmt.Errorf("offset %d for element %d is smaller than previous offset %d", offset, i, prevOffset)
+				return nil, dr.Errorf("offset %d for element %d is smaller than previous offset %d", offset, i, prevOffset)
 			}
 			offsets[i] = offset
 			prevOffset = offset
@@ -138,7 +141,7 @@ func (td *ComplexVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, err
 			}
 			el, err := td.ElemType.Deserialize(sub)
 			if err != nil {
-				return nil, err
+				return nil, dr.WrapIndex(uint64(i), err)
 			}
 			elements[i] = el
 		}
@@ -148,7 +151,7 @@ func (td *ComplexVectorTypeDef) Deserialize(dr *codec.DecodingReader) (View, err
 		}
 		el, err := td.ElemType.Deserialize(sub)
 		if err != nil {
-			return nil, err
+			return nil, dr.WrapIndex(uint64(lastIndex), err)
 		}
 		elements[lastIndex] = el
 		return td.FromElements(elements...)
@@ -227,6 +230,24 @@ func (tv *ComplexVectorView) ReadonlyIter() ElemIter {
 	return elemReadonlyIter(tv.BackingNode, tv.VectorLength, tv.depth, tv.ElemType)
 }
 
+// ReadonlyIterRange iterates over the elements [start, end), without visiting the subtrees of other elements.
+// Like ReadonlyIter, the elements are not bound to the vector, and may share the same view instance between steps.
+func (tv *ComplexVectorView) ReadonlyIterRange(start uint64, end uint64) ElemIter {
+	if err := checkIterRange(start, end, tv.VectorLength); err != nil {
+		return ErrElemIter{err}
+	}
+	return elemRangeIter(tv.BackingNode, tv.depth, tv.ElemType, start, end, false)
+}
+
+// ReadonlyIterReverse iterates over the elements [start, end) in reverse order, starting at end-1.
+// Like ReadonlyIter, the elements are not bound to the vector, and may share the same view instance between steps.
+func (tv *ComplexVectorView) ReadonlyIterReverse(start uint64, end uint64) ElemIter {
+	if err := checkIterRange(start, end, tv.VectorLength); err != nil {
+		return ErrElemIter{err}
+	}
+	return elemRangeIter(tv.BackingNode, tv.depth, tv.ElemType, start, end, true)
+}
+
 func (tv *ComplexVectorView) ValueByteLength() (uint64, error) {
 	if tv.IsFixedSize {
 		return tv.Size, nil
@@ -18,6 +18,8 @@ type ContainerTypeDef struct {
 	Fields        []FieldDef
 	OffsetsCount  uint64
 	FixedPartSize uint64
+	// field index by name
+	fieldIndices map[string]uint64
 }
 
 func ContainerType(name string, fields []FieldDef) *ContainerTypeDef {
@@ -25,7 +27,12 @@ func ContainerType(name string, fields []FieldDef) *ContainerTypeDef {
 	maxSize := uint64(0)
 	fixedPart := uint64(0)
 	offsetsCount := uint64(0)
-	for _, f := range fields {
+	fieldIndices := make(map[string]uint64, len(fields))
+	for i, f := range fields {
+		if _, ok := fieldIndices[f.Name]; ok {
+			panic(fmt.Errorf("container %s has duplicate field %q", name, f.Name))
+		}
+		fieldIndices[f.Name] = uint64(i)
 		if f.Type.IsFixedByteLength() {
 			size := f.Type.TypeByteLength()
 			fixedPart += size
@@ -54,9 +61,42 @@ func ContainerType(name string, fields []FieldDef) *ContainerTypeDef {
 		Fields:        fields,
 		OffsetsCount:  offsetsCount,
 		FixedPartSize: fixedPart,
+		fieldIndices:  fieldIndices,
 	}
 }
 
+// Extend creates a new container type with the fields of this container, followed by the extra fields.
+func (td *ContainerTypeDef) Extend(name string, extraFields ...FieldDef) *ContainerTypeDef {
+	fields := make([]FieldDef, 0, len(td.Fields)+len(extraFields))
+	fields = append(fields, td.Fields...)
+	fields = append(fields, extraFields...)
+	return ContainerType(name, fields)
+}
+
+// FieldIndex returns the index of the field with the given name.
+func (td *ContainerTypeDef) FieldIndex(name string) (uint64, bool) {
+	if td.fieldIndices == nil {
+		// type was not created with ContainerType
+		for i, f := range td.Fields {
+			if f.Name == name {
+				return uint64(i), true
+			}
+		}
+		return 0, false
+	}
+	i, ok := td.fieldIndices[name]
+	return i, ok
+}
+
+// FieldByName returns the field with the given name.
+func (td *ContainerTypeDef) FieldByName(name string) (FieldDef, bool) {
+	i, ok := td.FieldIndex(name)
+	if !ok {
+		return FieldDef{}, false
+	}
+	return td.Fields[i], true
+}
+
 func (td *ContainerTypeDef) FromFields(v ...View) (*ContainerView, error) {
 	if len(td.Fields) != len(v) {
 		return nil, fmt.Errorf("expected %d fields, got %d", len(td.Fields), len(v))
@@ -131,7 +171,7 @@ func (td *ContainerTypeDef) Deserialize(dr *codec.DecodingReader) (View, error)
 	prevOffset := uint32(td.FixedPartSize)
 	scope := dr.Scope()
 	if err := td.checkScope(scope); err != nil {
-		return nil, err
+		return nil, dr.WrapErr(err)
 	}
 	// Deserialize the fixed part: fixed-size fields and offsets to dynamic fields
 	for i, f := range td.Fields {
@@ -142,19 +182,22 @@ func (td *ContainerTypeDef) Deserialize(dr *codec.DecodingReader) (View, error)
 			}
 			v, err := f.Type.Deserialize(sub)
 			if err != nil {
-				return nil, err
+				return nil, dr.WrapField(f.Name, err)
 			}
 			fields[i] = v
 		} else {
 			offset, err := dr.ReadOffset()
 			if err != nil {
-				return nil, err
+				return nil, dr.WrapField(f.Name, err)
+			}
+			if len(offsets) == 0 && uint64(offset) != td.FixedPartSize {
+				return nil, dr.Errorf("first offset %d of field %d does not match fixed part size %d", offset, i, td.FixedPartSize)
 			}
 			if offset < prevOffset {
-				return nil, fmt.Errorf("offset %d of field %d is smaller than prev offset %d", offset, i, prevOffset)
+				return nil, dr.Errorf("offset %d of field %d is smaller than prev offset %d", offset, i, prevOffset)
 			}
 			if uint64(offset) > scope {
-				return nil, fmt.Errorf("offset %d of field %d is too big for scope %d", offset, i, scope)
+				return nil, dr.Errorf("offset %d of field %d is too big for scope %d", offset, i, scope)
 			}
 			prevOffset = offset
 			offsets = append(offsets, offsetField{index: i, offset: offset})
@@ -174,7 +217,7 @@ func (td *ContainerTypeDef) Deserialize(dr *codec.DecodingReader) (View, error)
 		}
 		v, err := td.Fields[item.index].Type.Deserialize(sub)
 		if err != nil {
-			return nil, err
+			return nil, dr.WrapField(td.Fields[item.index].Name, err)
 		}
 		fields[item.index] = v
 	}
@@ -341,6 +384,24 @@ func (tv *ContainerView) Get(i uint64) (View, error) {
 	return tv.ContainerTypeDef.Fields[i].Type.ViewFromBacking(v, tv.ItemHook(i))
 }
 
+// GetByName gets the field with the given name.
+func (tv *ContainerView) GetByName(name string) (View, error) {
+	i, ok := tv.FieldIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("container %s has no field %q", tv.ContainerName, name)
+	}
+	return tv.Get(i)
+}
+
+// SetByName sets the field with the given name.
+func (tv *ContainerView) SetByName(name string, v View) error {
+	i, ok := tv.FieldIndex(name)
+	if !ok {
+		return fmt.Errorf("container %s has no field %q", tv.ContainerName, name)
+	}
+	return tv.Set(i, v)
+}
+
 func (tv *ContainerView) Set(i uint64, v View) error {
 	return tv.setNode(i, v.Backing())
 }
@@ -0,0 +1,55 @@
+package view
+
+import (
+	"testing"
+)
+
+func TestContainerFieldByName(t *testing.T) {
+	if i, ok := VarTestStructType.FieldIndex("C"); !ok || i != 2 {
+		t.Fatalf("expected field C at index 2, got %d, %v", i, ok)
+	}
+	if _, ok := VarTestStructType.FieldIndex("D"); ok {
+		t.Fatal("expected no field D")
+	}
+	if f, ok := VarTestStructType.FieldByName("B"); !ok || f.Name != "B" || f.Type != VarTestStructType.Fields[1].Type {
+		t.Fatalf("unexpected field B: %v", f)
+	}
+	c := VarTestStructType.New()
+	if err := c.SetByName("A", Uint16View(0x1234)); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := AsUint16(c.GetByName("A")); err != nil || v != 0x1234 {
+		t.Fatalf("unexpected field A: %d, %v", v, err)
+	}
+	if v, err := AsUint16(c.Get(0)); err != nil || v != 0x1234 {
+		t.Fatalf("unexpected field 0: %d, %v", v, err)
+	}
+	if _, err := c.GetByName("D"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	if err := c.SetByName("D", Uint16View(0)); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestContainerExtend(t *testing.T) {
+	extended := FixedTestStructType.Extend("FixedTestStructV2", FieldDef{Name: "D", Type: Uint16Type})
+	if extended.FieldCount() != 4 || FixedTestStructType.FieldCount() != 3 {
+		t.Fatalf("unexpected field counts %d and %d", extended.FieldCount(), FixedTestStructType.FieldCount())
+	}
+	if extended.TypeByteLength() != FixedTestStructType.TypeByteLength()+2 {
+		t.Fatalf("unexpected byte length %d", extended.TypeByteLength())
+	}
+	if i, ok := extended.FieldIndex("D"); !ok || i != 3 {
+		t.Fatalf("expected field D at index 3, got %d, %v", i, ok)
+	}
+	if _, ok := FixedTestStructType.FieldIndex("D"); ok {
+		t.Fatal("extending must not modify the original type")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for duplicate field")
+		}
+	}()
+	FixedTestStructType.Extend("Invalid", FieldDef{Name: "A", Type: Uint16Type})
+}
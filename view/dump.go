@@ -0,0 +1,330 @@
+package view
+
+import (
+	"encoding/hex"
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+	"io"
+	"strings"
+)
+
+// DumpOptions limits the output of Dump.
+type DumpOptions struct {
+	// MaxDepth limits how deep values are nested in the output. Deeper values are printed with just their root.
+	// 0 for no limit.
+	MaxDepth int
+	// MaxLength limits the number of elements printed of each list and vector,
+	// and the number of bits and bytes printed of bitfields and byte strings. 0 for no limit.
+	MaxLength uint64
+}
+
+// Dump writes an indented tree of the view, one line per value, with the field name or index,
+// the type, the gindex relative to the root of v (0 if it does not fit in 64 bits), and the value or the root.
+// Roots of pair nodes are marked with whether they were cached, or had to be computed (and are cached now).
+// Subtrees that are summarized into a single root, and can thus not be navigated, are marked as such.
+// Errors while navigating the view are printed inline, only errors of the writer are returned.
+// The options may be nil, to print everything. See DumpNode to print the raw backing tree.
+func Dump(w io.Writer, v View, h HashFn, opts *DumpOptions) error {
+	if opts == nil {
+		opts = &DumpOptions{}
+	}
+	d := viewDumper{h: h, opts: opts}
+	d.dumpView("", v, 1, 0)
+	for _, line := range d.lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type viewDumper struct {
+	h     HashFn
+	opts  *DumpOptions
+	lines []string
+}
+
+func (d *viewDumper) line(depth int, format string, args ...interface{}) {
+	d.lines = append(d.lines, strings.Repeat("  ", depth)+fmt.Sprintf(format, args...)+"\n")
+}
+
+func (d *viewDumper) dumpView(label string, v View, g Gindex64, depth int) {
+	t := v.Type()
+	head := fmt.Sprintf("%s%s g=%d", label, t.String(), g)
+	switch tv := v.(type) {
+	case *ByteListView:
+		b, err := tv.Bytes()
+		if err != nil {
+			d.line(depth, "%s error: %v", head, err)
+			return
+		}
+		d.line(depth, "%s root=%s length=%d = %s", head, d.root(tv), len(b), d.bytesString(b))
+		return
+	case *ByteVectorView:
+		b, err := tv.Bytes()
+		if err != nil {
+			d.line(depth, "%s error: %v", head, err)
+			return
+		}
+		d.line(depth, "%s root=%s = %s", head, d.root(tv), d.bytesString(b))
+		return
+	case *BitListView:
+		length, err := tv.Length()
+		if err != nil {
+			d.line(depth, "%s error: %v", head, err)
+			return
+		}
+		d.line(depth, "%s root=%s length=%d = %s", head, d.root(tv), length, d.bitsString(tv.Get, length))
+		return
+	case *BitVectorView:
+		d.line(depth, "%s root=%s = %s", head, d.root(tv), d.bitsString(tv.Get, tv.BitLength))
+		return
+	}
+	switch InfoOf(t).Kind {
+	case KindUint, KindBool, KindRoot, KindSmallByteVector:
+		d.line(depth, "%s = %s", head, valueString(v))
+		return
+	}
+	// reserve the line, the root is computed after the children are printed,
+	// to not mark the children as cached when they were not.
+	i := len(d.lines)
+	d.lines = append(d.lines, "")
+	status := cacheStatus(v.Backing())
+	extra := ""
+	if d.opts.MaxDepth == 0 || depth < d.opts.MaxDepth {
+		extra = d.dumpChildren(v, g, depth+1)
+	}
+	d.lines[i] = fmt.Sprintf("%s%s root=%s%s%s\n", strings.Repeat("  ", depth), head, v.HashTreeRoot(d.h), status, extra)
+}
+
+// dumpChildren prints the fields or elements of v, and returns any extra information for the line of v itself.
+func (d *viewDumper) dumpChildren(v View, g Gindex64, depth int) string {
+	info := InfoOf(v.Type())
+	switch tv := v.(type) {
+	case *ContainerView:
+		for i, f := range tv.Fields {
+			d.dumpChild(f.Name+": ", tv.BackingNode, subtreeGindex(uint64(i), info.Depth), g, f.Type, depth)
+		}
+	case *StableContainerView:
+		for i, f := range tv.Fields {
+			if active, err := tv.IsActive(uint64(i)); err != nil {
+				d.line(depth, "%s: error: %v", f.Name, err)
+			} else if !active {
+				d.line(depth, "%s: %s absent", f.Name, f.Type.String())
+			} else {
+				d.dumpChild(f.Name+": ", tv.BackingNode, subtreeGindex(uint64(i), info.Depth), g, f.Type, depth)
+			}
+		}
+	case *ProfileView:
+		for i, f := range tv.Fields {
+			if active, err := tv.IsActive(uint64(i)); err != nil {
+				d.line(depth, "%s: error: %v", f.Name, err)
+			} else if !active {
+				d.line(depth, "%s: %s absent", f.Name, f.Type.String())
+			} else {
+				d.dumpChild(f.Name+": ", tv.BackingNode, subtreeGindex(tv.BaseIndices[i], info.Depth), g, f.Type, depth)
+			}
+		}
+	case *ComplexListView:
+		return d.dumpComplexElems(tv.Length, tv.BackingNode, g, info, depth)
+	case *ComplexVectorView:
+		return d.dumpComplexElems(func() (uint64, error) { return tv.VectorLength, nil }, tv.BackingNode, g, info, depth)
+	case *ComplexProgressiveListView:
+		return d.dumpComplexElems(tv.Length, tv.BackingNode, g, info, depth)
+	case *BasicListView:
+		return d.dumpBasicElems(tv.Length, tv.Get, g, info, depth)
+	case *BasicVectorView:
+		return d.dumpBasicElems(func() (uint64, error) { return tv.VectorLength, nil }, tv.Get, g, info, depth)
+	case *BasicProgressiveListView:
+		return d.dumpBasicElems(tv.Length, tv.Get, g, info, depth)
+	case *UnionView:
+		selector, err := tv.Selector()
+		if err != nil {
+			d.line(depth, "error: %v", err)
+			return ""
+		}
+		if opt := tv.Options[selector]; opt == nil {
+			d.line(depth, "value: None")
+		} else {
+			d.dumpChild("value: ", tv.BackingNode, LeftGindex, g, opt, depth)
+		}
+		return fmt.Sprintf(" selector=%d", selector)
+	case *OptionalView:
+		some, err := tv.IsSome()
+		if err != nil {
+			d.line(depth, "error: %v", err)
+			return ""
+		}
+		if !some {
+			return " none"
+		}
+		d.dumpChild("value: ", tv.BackingNode, LeftGindex, g, tv.ElemType, depth)
+		return " some"
+	default:
+		d.line(depth, "cannot dump %T", v)
+	}
+	return ""
+}
+
+func (d *viewDumper) dumpComplexElems(length func() (uint64, error), node Node, g Gindex64, info TypeInfo, depth int) string {
+	n, err := length()
+	if err != nil {
+		d.line(depth, "error: %v", err)
+		return ""
+	}
+	for i := uint64(0); i < d.limit(n); i++ {
+		rel := subtreeGindex(i, info.Depth)
+		if info.Kind == KindComplexProgressiveList {
+			rel = progressiveGindex(i)
+		}
+		d.dumpChild(fmt.Sprintf("[%d]: ", i), node, rel, g, info.Elem, depth)
+	}
+	d.more(n, depth)
+	return fmt.Sprintf(" length=%d", n)
+}
+
+func (d *viewDumper) dumpBasicElems(length func() (uint64, error), get func(i uint64) (BasicView, error),
+	g Gindex64, info TypeInfo, depth int) string {
+	n, err := length()
+	if err != nil {
+		d.line(depth, "error: %v", err)
+		return ""
+	}
+	perChunk := 32 / info.Elem.TypeByteLength()
+	for i := uint64(0); i < d.limit(n); i++ {
+		// basic elements are packed, the gindex is that of the chunk
+		rel := subtreeGindex(i/perChunk, info.Depth)
+		if info.Kind == KindBasicProgressiveList {
+			rel = progressiveGindex(i / perChunk)
+		}
+		label := fmt.Sprintf("[%d]: %s g=%d", i, info.Elem.String(), g.Concat(rel))
+		if v, err := get(i); err != nil {
+			d.line(depth, "%s error: %v", label, err)
+		} else {
+			d.line(depth, "%s = %s", label, valueString(v))
+		}
+	}
+	d.more(n, depth)
+	return fmt.Sprintf(" length=%d", n)
+}
+
+func (d *viewDumper) dumpChild(label string, parent Node, rel Gindex64, g Gindex64, t TypeDef, depth int) {
+	g = g.Concat(rel)
+	node, err := parent.Getter(rel)
+	if err != nil {
+		d.line(depth, "%s%s g=%d error: %v", label, t.String(), g, err)
+		return
+	}
+	if node.IsLeaf() && expectsSubtree(t) {
+		d.line(depth, "%s%s g=%d root=%s summarized", label, t.String(), g, node.MerkleRoot(d.h))
+		return
+	}
+	v, err := t.ViewFromBacking(node, nil)
+	if err != nil {
+		d.line(depth, "%s%s g=%d error: %v", label, t.String(), g, err)
+		return
+	}
+	d.dumpView(label, v, g, depth)
+}
+
+func (d *viewDumper) root(v View) string {
+	status := cacheStatus(v.Backing())
+	return v.HashTreeRoot(d.h).String() + status
+}
+
+func (d *viewDumper) limit(n uint64) uint64 {
+	if d.opts.MaxLength != 0 && n > d.opts.MaxLength {
+		return d.opts.MaxLength
+	}
+	return n
+}
+
+func (d *viewDumper) more(n uint64, depth int) {
+	if m := d.limit(n); m < n {
+		d.line(depth, "... %d more", n-m)
+	}
+}
+
+func (d *viewDumper) bytesString(b []byte) string {
+	if m := d.limit(uint64(len(b))); m < uint64(len(b)) {
+		return "0x" + hex.EncodeToString(b[:m]) + "..."
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (d *viewDumper) bitsString(get func(i uint64) (BoolView, error), n uint64) string {
+	var buf strings.Builder
+	buf.WriteString("0b")
+	for i := uint64(0); i < d.limit(n); i++ {
+		bit, err := get(i)
+		if err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		if bit {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	}
+	if d.limit(n) < n {
+		buf.WriteString("...")
+	}
+	return buf.String()
+}
+
+// cacheStatus returns whether the root of the node is cached, must be called before the root is computed.
+func cacheStatus(n Node) string {
+	if p, ok := n.(*PairNode); ok {
+		if p.Value != (Root{}) {
+			return " cached"
+		}
+		return " computed"
+	}
+	return ""
+}
+
+func valueString(v View) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	b, err := SerializeToBytes(v)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// expectsSubtree returns true if values of the type are backed by more than a single leaf node.
+func expectsSubtree(t TypeDef) bool {
+	info := InfoOf(t)
+	switch info.Kind {
+	case KindBasicProgressiveList, KindComplexProgressiveList:
+		return true
+	case KindContainer:
+		if info.Depth == 0 && len(info.Fields) == 1 {
+			return expectsSubtree(info.Fields[0].Type)
+		}
+	case KindComplexVector:
+		if info.Depth == 0 && info.Length == 1 {
+			return expectsSubtree(info.Elem)
+		}
+	}
+	return info.Depth > 0
+}
+
+func subtreeGindex(i uint64, depth uint8) Gindex64 {
+	g, err := ToGindex64(i, depth)
+	if err != nil {
+		return 0
+	}
+	return g
+}
+
+// progressiveGindex returns the gindex of chunk i of a progressive list, relative to the list root.
+func progressiveGindex(i uint64) Gindex64 {
+	g, err := ProgressiveGindex(i)
+	if err != nil {
+		return 0
+	}
+	return LeftGindex.Concat(g)
+}
@@ -0,0 +1,105 @@
+package view
+
+import (
+	"github.com/protolambda/ztyp/tree"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	hFn := tree.GetHashFn()
+	v, err := VarTestStructType.FromFields(Uint16View(0xabcd), BasicListType(Uint16Type, 1024).New(), Uint8View(0x42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := AsBasicList(v.Get(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := list.Append(Uint16View(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf strings.Builder
+	if err := Dump(&buf, v, hFn, &DumpOptions{MaxLength: 2}); err != nil {
+		t.Fatal(err)
+	}
+	// the elements are packed 16 per chunk, the first chunk is at depth 7 (64 chunks, and the length mix-in) below the list
+	expected := []string{
+		"VarTestStruct g=1 root=" + v.HashTreeRoot(hFn).String() + " computed",
+		"  A: uint16 g=4 = 43981",
+		"  B: List[uint16, 1024] g=5 root=" + list.HashTreeRoot(hFn).String() + " computed length=20",
+		"    [0]: uint16 g=640 = 0",
+		"    [1]: uint16 g=640 = 1",
+		"    ... 18 more",
+		"  C: uint8 g=6 = 66",
+		"",
+	}
+	if got := buf.String(); got != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected dump:\n%s", got)
+	}
+	// the printed gindex of the list points at its backing node
+	listNode, err := v.Backing().Getter(tree.Gindex64(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listNode.MerkleRoot(hFn) != list.HashTreeRoot(hFn) {
+		t.Fatal("gindex of list does not match")
+	}
+
+	// the roots are cached now, and the depth is limited
+	buf.Reset()
+	if err := Dump(&buf, v, hFn, &DumpOptions{MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "VarTestStruct g=1 root="+v.HashTreeRoot(hFn).String()+" cached\n") {
+		t.Fatalf("expected cached root:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "[0]") {
+		t.Fatalf("expected no list elements:\n%s", buf.String())
+	}
+}
+
+func TestDumpSummarized(t *testing.T) {
+	hFn := tree.GetHashFn()
+	v := ComplexTestStructType.New()
+	// collapse field E into just its root
+	link, err := tree.SummaryInto(v.Backing(), tree.Gindex64(12), hFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := link()
+	if err != nil {
+		t.Fatal(err)
+	}
+	summarized, err := ComplexTestStructType.ViewFromBacking(node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := Dump(&buf, summarized, hFn, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n  E: VarTestStruct g=12 root="+VarTestStructType.New().HashTreeRoot(hFn).String()+" summarized\n") {
+		t.Fatalf("expected summarized field:\n%s", buf.String())
+	}
+}
+
+func TestDumpUnion(t *testing.T) {
+	hFn := tree.GetHashFn()
+	opt := OptionalType(Uint64Type)
+	some, err := opt.Some(Uint64View(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := Dump(&buf, some, hFn, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Optional[uint64] g=1 root=" + some.HashTreeRoot(hFn).String() + " computed some\n" +
+		"  value: uint64 g=2 = 7\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("unexpected dump:\n%s", got)
+	}
+}
@@ -0,0 +1,187 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+	"reflect"
+)
+
+// TypeEqual checks if two type definitions describe the same type:
+// the same shape, the same lengths and limits, and the same field names.
+// Container names are not compared.
+func TypeEqual(a TypeDef, b TypeDef) bool {
+	return typeEqual(a, b, true)
+}
+
+// TypeCompatible checks if two type definitions have the same shape and limits, ignoring field names.
+// Views of compatible types have the same SSZ encoding and merkleization,
+// and can be converted into each other by re-using the backing.
+// E.g. a ByteVector is compatible with a BasicVector of the same length with Uint8 elements.
+func TypeCompatible(a TypeDef, b TypeDef) bool {
+	return typeEqual(a, b, false)
+}
+
+func typeEqual(a TypeDef, b TypeDef, names bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a).Comparable() && reflect.TypeOf(b).Comparable() && a == b {
+		return true
+	}
+	if !names {
+		// byte types are packed the same as their uint8 equivalents
+		a, b = asUint8Seq(a), asUint8Seq(b)
+	}
+	switch x := a.(type) {
+	case UintMeta, BoolMeta, RootMeta, SmallByteVecMeta:
+		// basic metas are values, already compared above
+		return false
+	case *ContainerTypeDef:
+		y, ok := b.(*ContainerTypeDef)
+		return ok && fieldsEqual(x.Fields, y.Fields, names)
+	case *StableContainerTypeDef:
+		y, ok := b.(*StableContainerTypeDef)
+		return ok && x.Capacity == y.Capacity && fieldsEqual(x.Fields, y.Fields, names)
+	case *ProfileTypeDef:
+		y, ok := b.(*ProfileTypeDef)
+		if !ok || len(x.Fields) != len(y.Fields) || !typeEqual(x.Base, y.Base, names) {
+			return false
+		}
+		for i, f := range x.Fields {
+			g := y.Fields[i]
+			if x.BaseIndices[i] != y.BaseIndices[i] || f.Optional != g.Optional ||
+				(names && f.Name != g.Name) || !typeEqual(f.Type, g.Type, names) {
+				return false
+			}
+		}
+		return true
+	case *ComplexListTypeDef:
+		y, ok := b.(*ComplexListTypeDef)
+		return ok && x.ListLimit == y.ListLimit && typeEqual(x.ElemType, y.ElemType, names)
+	case *ComplexVectorTypeDef:
+		y, ok := b.(*ComplexVectorTypeDef)
+		return ok && x.VectorLength == y.VectorLength && typeEqual(x.ElemType, y.ElemType, names)
+	case *BasicListTypeDef:
+		y, ok := b.(*BasicListTypeDef)
+		return ok && x.ListLimit == y.ListLimit && typeEqual(x.ElemType, y.ElemType, names)
+	case *BasicVectorTypeDef:
+		y, ok := b.(*BasicVectorTypeDef)
+		return ok && x.VectorLength == y.VectorLength && typeEqual(x.ElemType, y.ElemType, names)
+	case *BasicProgressiveListTypeDef:
+		y, ok := b.(*BasicProgressiveListTypeDef)
+		return ok && typeEqual(x.ElemType, y.ElemType, names)
+	case *ComplexProgressiveListTypeDef:
+		y, ok := b.(*ComplexProgressiveListTypeDef)
+		return ok && typeEqual(x.ElemType, y.ElemType, names)
+	case *BitListTypeDef:
+		y, ok := b.(*BitListTypeDef)
+		return ok && x.BitLimit == y.BitLimit
+	case *BitVectorTypeDef:
+		y, ok := b.(*BitVectorTypeDef)
+		return ok && x.BitLength == y.BitLength
+	case *ByteListTypeDef:
+		y, ok := b.(*ByteListTypeDef)
+		return ok && x.ListLimit == y.ListLimit
+	case *ByteVectorTypeDef:
+		y, ok := b.(*ByteVectorTypeDef)
+		return ok && x.VectorLength == y.VectorLength
+	case *OptionalTypeDef:
+		y, ok := b.(*OptionalTypeDef)
+		return ok && typeEqual(x.ElemType, y.ElemType, names)
+	case *UnionTypeDef:
+		y, ok := b.(*UnionTypeDef)
+		if !ok || len(x.Options) != len(y.Options) {
+			return false
+		}
+		for i, opt := range x.Options {
+			if !typeEqual(opt, y.Options[i], names) {
+				return false
+			}
+		}
+		return true
+	default:
+		// unknown types are only equal to themselves
+		return false
+	}
+}
+
+func fieldsEqual(a []FieldDef, b []FieldDef, names bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, f := range a {
+		if (names && f.Name != b[i].Name) || !typeEqual(f.Type, b[i].Type, names) {
+			return false
+		}
+	}
+	return true
+}
+
+func asUint8Seq(t TypeDef) TypeDef {
+	switch x := t.(type) {
+	case *ByteVectorTypeDef:
+		return BasicVectorType(Uint8Type, x.VectorLength)
+	case *ByteListTypeDef:
+		return BasicListType(Uint8Type, x.ListLimit)
+	default:
+		return t
+	}
+}
+
+// Equal checks if two views have equal types (see TypeEqual) and equal contents.
+// Subtrees that are shared, or that already have a cached root, are not traversed.
+// Other subtrees are compared node by node, and only hashed where the two trees differ in shape,
+// e.g. when one of them is summarized.
+func Equal(a View, b View) (bool, error) {
+	if !TypeEqual(a.Type(), b.Type()) {
+		return false, nil
+	}
+	return nodesEqual(a.Backing(), b.Backing())
+}
+
+func nodesEqual(a Node, b Node) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	if x, ok := a.(*Root); ok {
+		if y, ok := b.(*Root); ok {
+			return *x == *y, nil
+		}
+	}
+	if x, ok := a.(*PairNode); ok {
+		if y, ok := b.(*PairNode); ok {
+			if x.Value != (Root{}) && y.Value != (Root{}) {
+				return x.Value == y.Value, nil
+			}
+			left, err := nodesEqual(x.LeftChild, y.LeftChild)
+			if err != nil || !left {
+				return false, err
+			}
+			return nodesEqual(x.RightChild, y.RightChild)
+		}
+	}
+	if a == nil || b == nil {
+		return false, fmt.Errorf("cannot compare missing node")
+	}
+	// different kinds of nodes, fall back to comparing the roots
+	hFn := GetHashFn()
+	return a.MerkleRoot(hFn) == b.MerkleRoot(hFn), nil
+}
+
+// Compare orders two views of equal types (see TypeEqual) by their SSZ encoding.
+// The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func Compare(a View, b View) (int, error) {
+	if !TypeEqual(a.Type(), b.Type()) {
+		return 0, fmt.Errorf("cannot compare views of different types %s and %s", a.Type().String(), b.Type().String())
+	}
+	x, err := SerializeToBytes(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := SerializeToBytes(b)
+	if err != nil {
+		return 0, err
+	}
+	return bytes.Compare(x, y), nil
+}
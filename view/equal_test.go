@@ -0,0 +1,142 @@
+package view
+
+import (
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestTypeEqual(t *testing.T) {
+	renamed := ContainerType("Other", []FieldDef{
+		{Name: "X", Type: Uint8Type},
+		{Name: "Y", Type: Uint64Type},
+		{Name: "Z", Type: Uint32Type},
+	})
+	sameNames := ContainerType("Other", []FieldDef{
+		{Name: "A", Type: Uint8Type},
+		{Name: "B", Type: Uint64Type},
+		{Name: "C", Type: Uint32Type},
+	})
+	cases := []struct {
+		name       string
+		a, b       TypeDef
+		equal      bool
+		compatible bool
+	}{
+		{"same basic", Uint64Type, Uint64Type, true, true},
+		{"different basic", Uint64Type, Uint32Type, false, false},
+		{"container field names", FixedTestStructType, renamed, false, true},
+		{"container name", FixedTestStructType, sameNames, true, true},
+		{"container shape", FixedTestStructType, SmallTestStructType, false, false},
+		{"list limit", ListType(Uint16Type, 1024), ListType(Uint16Type, 1023), false, false},
+		{"list elem", ListType(FixedTestStructType, 4), ListType(renamed, 4), false, true},
+		{"list", ListType(FixedTestStructType, 4), ComplexListType(sameNames, 4), true, true},
+		{"list and vector", ListType(Uint16Type, 4), VectorType(Uint16Type, 4), false, false},
+		{"bitlist", BitListType(8), BitListType(8), true, true},
+		{"bitvector", BitVectorType(8), BitVectorType(9), false, false},
+		{"byte vector", ByteVectorType(48), VectorType(Uint8Type, 48), false, true},
+		{"byte list", ByteListType(48), ListType(Uint8Type, 48), false, true},
+		{"union", UnionType([]TypeDef{nil, FixedTestStructType}), UnionType([]TypeDef{nil, sameNames}), true, true},
+		{"union options", UnionType([]TypeDef{nil, Uint8Type}), UnionType([]TypeDef{Uint8Type, Uint8Type}), false, false},
+		{"optional", OptionalType(renamed), OptionalType(FixedTestStructType), false, true},
+		{"stable container", ShapeType, ShapeType, true, true},
+		{"profiles", SquareType, CircleType, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := TypeEqual(c.a, c.b); got != c.equal {
+				t.Errorf("expected equal %v, got %v", c.equal, got)
+			}
+			if got := TypeEqual(c.b, c.a); got != c.equal {
+				t.Errorf("expected reverse equal %v, got %v", c.equal, got)
+			}
+			if got := TypeCompatible(c.a, c.b); got != c.compatible {
+				t.Errorf("expected compatible %v, got %v", c.compatible, got)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	hFn := tree.GetHashFn()
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if !TypeEqual(tt.value.Type(), tt.value.Type()) {
+				t.Fatal("type not equal to itself")
+			}
+			cpy, err := tt.value.Copy()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eq, err := Equal(tt.value, cpy); err != nil || !eq {
+				t.Fatalf("copy not equal: %v", err)
+			}
+			// decoded views have no cached roots, and do not share any nodes
+			enc, err := SerializeToBytes(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := tt.value.Type().Deserialize(codec.NewBytesDecodingReader(enc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eq, err := Equal(tt.value, decoded); err != nil || !eq {
+				t.Fatalf("decoded view not equal: %v", err)
+			}
+			if c, err := Compare(tt.value, decoded); err != nil || c != 0 {
+				t.Fatalf("decoded view compares as %d: %v", c, err)
+			}
+			// cached roots are compared directly
+			tt.value.HashTreeRoot(hFn)
+			decoded.HashTreeRoot(hFn)
+			if eq, err := Equal(tt.value, decoded); err != nil || !eq {
+				t.Fatalf("hashed decoded view not equal: %v", err)
+			}
+		})
+	}
+}
+
+func TestEqualDifferent(t *testing.T) {
+	td := BasicListType(Uint16Type, 1024)
+	a, err := td.FromElements(Uint16View(1), Uint16View(2), Uint16View(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := td.FromElements(Uint16View(1), Uint16View(2), Uint16View(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eq, err := Equal(a, b); err != nil || eq {
+		t.Fatalf("expected lists to differ: %v", err)
+	}
+	if c, err := Compare(a, b); err != nil || c != -1 {
+		t.Fatalf("expected a < b, got %d: %v", c, err)
+	}
+	// summarized trees are compared by root
+	hFn := tree.GetHashFn()
+	summary := td.New()
+	contentsRoot, err := a.BackingNode.Getter(tree.LeftGindex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lengthNode, err := a.BackingNode.Getter(tree.RightGindex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := contentsRoot.MerkleRoot(hFn)
+	if err := summary.SetBacking(tree.NewPairNode(&r, lengthNode)); err != nil {
+		t.Fatal(err)
+	}
+	if eq, err := Equal(a, summary); err != nil || !eq {
+		t.Fatalf("expected summarized list to be equal: %v", err)
+	}
+	if eq, err := Equal(b, summary); err != nil || eq {
+		t.Fatalf("expected summarized list to differ: %v", err)
+	}
+	if eq, err := Equal(a, BasicListType(Uint16Type, 1023).New()); err != nil || eq {
+		t.Fatalf("expected different types to differ: %v", err)
+	}
+	if _, err := Compare(a, Uint16View(1)); err == nil {
+		t.Fatal("expected error comparing different types")
+	}
+}
@@ -0,0 +1,356 @@
+package view
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/protolambda/ztyp/bitfields/typed"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"math/rand"
+	"testing"
+)
+
+// addFuzzSeeds adds random valid encodings of the type, and some invalid edge cases, to the seed corpus.
+func addFuzzSeeds(f *testing.F, typ TypeDef) {
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{1})
+	f.Add(bytes.Repeat([]byte{0xff}, 64))
+	for seed := int64(0); seed < 8; seed++ {
+		data, err := RandomBytes(typ, rand.New(rand.NewSource(seed)), &RandomOptions{MaxLength: 8})
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+}
+
+// fuzzDecode decodes the data in both buffer and stream mode, and checks that:
+//   - both modes, and the validator, agree on whether the input is valid.
+//   - valid input serializes back to the same bytes.
+//
+// It returns nil if the input is invalid.
+func fuzzDecode(t *testing.T, typ TypeDef, data []byte) View {
+	dr := codec.NewBytesDecodingReader(data)
+	v, err := typ.Deserialize(dr)
+	if err == nil && dr.Scope() != 0 {
+		// the decoding of fixed-size types may not consume the full input
+		if !typ.IsFixedByteLength() {
+			t.Fatalf("decoding %x did not consume %d bytes", data, dr.Scope())
+		}
+		return nil
+	}
+	streamed, streamErr := typ.Deserialize(codec.NewDecodingReader(bytes.NewReader(data), uint64(len(data))))
+	validateErr := ValidateBytes(typ, data)
+	if (err == nil) != (streamErr == nil) {
+		t.Fatalf("buffer and stream decoding disagree on %x: %v <> %v", data, err, streamErr)
+	}
+	if (err == nil) != (validateErr == nil) {
+		t.Fatalf("decoding and validation disagree on %x: %v <> %v", data, err, validateErr)
+	}
+	if err != nil {
+		return nil
+	}
+	out, err := SerializeToBytes(v)
+	if err != nil {
+		t.Fatalf("cannot serialize decoded %x: %v", data, err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatalf("decoded %x serializes to %x", data, out)
+	}
+	if size, err := v.ValueByteLength(); err != nil || size != uint64(len(data)) {
+		t.Fatalf("decoded %x has byte length %d: %v", data, size, err)
+	}
+	hFn := tree.GetHashFn()
+	if a, b := v.HashTreeRoot(hFn), streamed.HashTreeRoot(hFn); a != b {
+		t.Fatalf("buffer and stream decoding of %x have different roots %s <> %s", data, a, b)
+	}
+	return v
+}
+
+func fuzzCheckRoot(t *testing.T, v View, expected tree.Root) {
+	if got := v.HashTreeRoot(tree.GetHashFn()); got != expected {
+		t.Fatalf("view root %s does not match expected root %s", got, expected)
+	}
+}
+
+// fuzzElems collects the elements of a readonly iterator, which may re-use the same view between steps.
+func fuzzElems(t *testing.T, iter ElemIter) (out []View) {
+	for {
+		elem, ok, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return out
+		}
+		elem, err = elem.Copy()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, elem)
+	}
+}
+
+func FuzzBasicTypes(f *testing.F) {
+	addFuzzSeeds(f, Uint256Type)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, typ := range []TypeDef{Uint8Type, Uint16Type, Uint32Type, Uint64Type, Uint128Type, Uint256Type, BoolType, RootType} {
+			v := fuzzDecode(t, typ, data)
+			if v == nil {
+				continue
+			}
+			var expected tree.Root
+			copy(expected[:], data)
+			fuzzCheckRoot(t, v, expected)
+		}
+	})
+}
+
+func FuzzContainer(f *testing.F) {
+	addFuzzSeeds(f, ComplexTestStructType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, typ := range []*ContainerTypeDef{FixedTestStructType, VarTestStructType, ComplexTestStructType} {
+			v, _ := AsContainer(fuzzDecode(t, typ, data), nil)
+			if v == nil {
+				continue
+			}
+			fields, err := v.FieldValues()
+			if err != nil {
+				t.Fatal(err)
+			}
+			htrs := make([]tree.HTR, len(fields), len(fields))
+			for i, f := range fields {
+				htrs[i] = f
+			}
+			fuzzCheckRoot(t, v, tree.GetHashFn().HashTreeRoot(htrs...))
+		}
+	})
+}
+
+func FuzzComplexListVector(f *testing.F) {
+	listType := ComplexListType(VarTestStructType, 8)
+	vectorType := ComplexVectorType(VarTestStructType, 2)
+	addFuzzSeeds(f, listType)
+	addFuzzSeeds(f, vectorType)
+	// vector with a first offset past the offsets
+	f.Add([]byte("\x0c\x00\x00\x00\x13\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00\x00\x00\x00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if v, _ := AsComplexList(fuzzDecode(t, listType, data), nil); v != nil {
+			elems := fuzzElems(t, v.ReadonlyIter())
+			fuzzCheckRoot(t, v, tree.GetHashFn().ComplexListHTR(func(i uint64) tree.HTR {
+				return elems[i]
+			}, uint64(len(elems)), listType.ListLimit))
+		}
+		if v, _ := AsComplexVector(fuzzDecode(t, vectorType, data), nil); v != nil {
+			elems := fuzzElems(t, v.ReadonlyIter())
+			fuzzCheckRoot(t, v, tree.GetHashFn().ComplexVectorHTR(func(i uint64) tree.HTR {
+				return elems[i]
+			}, vectorType.VectorLength))
+		}
+	})
+}
+
+func FuzzBasicListVector(f *testing.F) {
+	listType := BasicListType(Uint64Type, 100)
+	vectorType := BasicVectorType(Uint64Type, 5)
+	byteListType := BasicListType(Uint8Type, 100)
+	addFuzzSeeds(f, listType)
+	addFuzzSeeds(f, vectorType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		u64 := func(i uint64) uint64 {
+			return binary.LittleEndian.Uint64(data[i*8:])
+		}
+		hFn := tree.GetHashFn()
+		if v := fuzzDecode(t, listType, data); v != nil {
+			fuzzCheckRoot(t, v, hFn.Uint64ListHTR(u64, uint64(len(data))/8, listType.ListLimit))
+		}
+		if v := fuzzDecode(t, vectorType, data); v != nil {
+			fuzzCheckRoot(t, v, hFn.Uint64VectorHTR(u64, vectorType.VectorLength))
+		}
+		if v := fuzzDecode(t, byteListType, data); v != nil {
+			fuzzCheckRoot(t, v, hFn.Uint8ListHTR(func(i uint64) uint8 {
+				return data[i]
+			}, uint64(len(data)), byteListType.ListLimit))
+		}
+	})
+}
+
+func FuzzByteListVector(f *testing.F) {
+	listType := ByteListType(100)
+	vectorType := ByteVectorType(48)
+	addFuzzSeeds(f, listType)
+	addFuzzSeeds(f, vectorType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hFn := tree.GetHashFn()
+		if v := fuzzDecode(t, listType, data); v != nil {
+			fuzzCheckRoot(t, v, hFn.ByteListHTR(data, listType.ListLimit))
+		}
+		if v := fuzzDecode(t, vectorType, data); v != nil {
+			fuzzCheckRoot(t, v, hFn.ByteVectorHTR(data))
+		}
+	})
+}
+
+func FuzzBitListVector(f *testing.F) {
+	listType := BitListType(1000)
+	vectorType := BitVectorType(100)
+	addFuzzSeeds(f, listType)
+	addFuzzSeeds(f, vectorType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hFn := tree.GetHashFn()
+		// the flat bitfields must agree with the views
+		flatList, _ := typed.NewBitlist(0, listType.BitLimit)
+		flatListErr := flatList.Deserialize(codec.NewBytesDecodingReader(data))
+		if flatListErr == nil && uint64(len(data)) != flatList.ByteLength() {
+			flatListErr = fmt.Errorf("trailing bytes")
+		}
+		v := fuzzDecode(t, listType, data)
+		if (v == nil) != (flatListErr != nil) {
+			t.Fatalf("bitlist view and flat bitlist disagree on %x: %v", data, flatListErr)
+		}
+		if v != nil {
+			fuzzCheckRoot(t, v, hFn.BitListHTR(data, listType.BitLimit))
+			fuzzCheckRoot(t, v, flatList.HashTreeRoot(hFn))
+		}
+		flatVector := typed.NewBitvector(vectorType.BitLength)
+		flatVectorErr := flatVector.Deserialize(codec.NewBytesDecodingReader(data))
+		if flatVectorErr == nil && uint64(len(data)) != flatVector.ByteLength() {
+			flatVectorErr = fmt.Errorf("trailing bytes")
+		}
+		v = fuzzDecode(t, vectorType, data)
+		if (v == nil) != (flatVectorErr != nil) {
+			t.Fatalf("bitvector view and flat bitvector disagree on %x: %v", data, flatVectorErr)
+		}
+		if v != nil {
+			fuzzCheckRoot(t, v, hFn.BitVectorHTR(data))
+			fuzzCheckRoot(t, v, flatVector.HashTreeRoot(hFn))
+		}
+	})
+}
+
+func FuzzUnion(f *testing.F) {
+	typ := UnionType([]TypeDef{nil, Uint16Type, VarTestStructType})
+	addFuzzSeeds(f, typ)
+	// None with a payload, and a fixed-size value with trailing bytes
+	f.Add([]byte{0, 0x30})
+	f.Add([]byte{1, 0x30, 0x30, 0x30})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, _ := AsUnion(fuzzDecode(t, typ, data), nil)
+		if v == nil {
+			return
+		}
+		selector, err := v.Selector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := v.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var htr tree.HTR
+		if value != nil {
+			htr = value
+		}
+		fuzzCheckRoot(t, v, tree.GetHashFn().Union(selector, htr))
+	})
+}
+
+func FuzzOptional(f *testing.F) {
+	varType := OptionalType(VarTestStructType)
+	fixedType := OptionalType(Uint32Type)
+	addFuzzSeeds(f, varType)
+	addFuzzSeeds(f, fixedType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, typ := range []*OptionalTypeDef{varType, fixedType} {
+			v, _ := fuzzDecode(t, typ, data).(*OptionalView)
+			if v == nil {
+				continue
+			}
+			value, err := v.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			hFn := tree.GetHashFn()
+			if value == nil {
+				fuzzCheckRoot(t, v, hFn(tree.Root{}, tree.Root{}))
+			} else {
+				fuzzCheckRoot(t, v, hFn(value.HashTreeRoot(hFn), tree.Root{0: 1}))
+			}
+		}
+	})
+}
+
+// fuzzShapeRoot merkleizes the ShapeType fields, absent fields are zero chunks, padded to the capacity,
+// and mixes in the root of the active fields bitvector.
+func fuzzShapeRoot(active byte, values [3][]byte) tree.Root {
+	hFn := tree.GetHashFn()
+	fields := hFn.ChunksHTR(func(i uint64) (out tree.Root) {
+		copy(out[:], values[i])
+		return
+	}, 3, 4)
+	return hFn(fields, hFn.BitVectorHTR([]byte{active}))
+}
+
+func FuzzStableContainer(f *testing.F) {
+	addFuzzSeeds(f, ShapeType)
+	addFuzzSeeds(f, SquareType)
+	addFuzzSeeds(f, CircleType)
+	sizes := [3]int{2, 1, 2}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// all fields are fixed-size, present fields follow the active fields bitvector
+		if v := fuzzDecode(t, ShapeType, data); v != nil {
+			var values [3][]byte
+			rest := data[1:]
+			for i, size := range sizes {
+				if data[0]&(1<<i) != 0 {
+					values[i], rest = rest[:size], rest[size:]
+				}
+			}
+			fuzzCheckRoot(t, v, fuzzShapeRoot(data[0], values))
+		}
+		// a square has side and color, without bitvector
+		if v := fuzzDecode(t, SquareType, data); v != nil {
+			fuzzCheckRoot(t, v, fuzzShapeRoot(0b011, [3][]byte{data[:2], data[2:3], nil}))
+		}
+		// a circle has color, and optionally radius, prefixed with a bitvector of the optional fields
+		if v := fuzzDecode(t, CircleType, data); v != nil {
+			if data[0] == 1 {
+				fuzzCheckRoot(t, v, fuzzShapeRoot(0b110, [3][]byte{nil, data[1:2], data[2:4]}))
+			} else {
+				fuzzCheckRoot(t, v, fuzzShapeRoot(0b010, [3][]byte{nil, data[1:2], nil}))
+			}
+		}
+	})
+}
+
+func FuzzProgressiveList(f *testing.F) {
+	basicType := BasicProgressiveListType(Uint64Type)
+	complexType := ComplexProgressiveListType(VarTestStructType)
+	addFuzzSeeds(f, basicType)
+	addFuzzSeeds(f, complexType)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hFn := tree.GetHashFn()
+		if v := fuzzDecode(t, basicType, data); v != nil {
+			length := uint64(len(data)) / 8
+			fuzzCheckRoot(t, v, hFn.Mixin(hFn.ProgressiveChunksHTR(func(i uint64) (out tree.Root) {
+				copy(out[:], data[i*32:])
+				return
+			}, (length+3)/4), length))
+		}
+		if v, _ := fuzzDecode(t, complexType, data).(*ComplexProgressiveListView); v != nil {
+			length, err := v.Length()
+			if err != nil {
+				t.Fatal(err)
+			}
+			fuzzCheckRoot(t, v, hFn.ProgressiveListHTR(func(i uint64) tree.HTR {
+				elem, err := v.Get(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return elem
+			}, length))
+		}
+	})
+}
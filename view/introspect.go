@@ -0,0 +1,285 @@
+package view
+
+import (
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// TypeKind identifies the kind of a type definition.
+type TypeKind uint8
+
+const (
+	KindUnknown TypeKind = iota
+	KindUint
+	KindBool
+	KindRoot
+	KindSmallByteVector
+	KindContainer
+	KindStableContainer
+	KindProfile
+	KindComplexList
+	KindComplexVector
+	KindBasicList
+	KindBasicVector
+	KindBasicProgressiveList
+	KindComplexProgressiveList
+	KindBitList
+	KindBitVector
+	KindByteList
+	KindByteVector
+	KindUnion
+	KindOptional
+)
+
+var typeKindNames = [...]string{
+	KindUnknown:                "unknown",
+	KindUint:                   "uint",
+	KindBool:                   "bool",
+	KindRoot:                   "root",
+	KindSmallByteVector:        "small byte vector",
+	KindContainer:              "container",
+	KindStableContainer:        "stable container",
+	KindProfile:                "profile",
+	KindComplexList:            "complex list",
+	KindComplexVector:          "complex vector",
+	KindBasicList:              "basic list",
+	KindBasicVector:            "basic vector",
+	KindBasicProgressiveList:   "basic progressive list",
+	KindComplexProgressiveList: "complex progressive list",
+	KindBitList:                "bitlist",
+	KindBitVector:              "bitvector",
+	KindByteList:               "byte list",
+	KindByteVector:             "byte vector",
+	KindUnion:                  "union",
+	KindOptional:               "optional",
+}
+
+func (k TypeKind) String() string {
+	if int(k) < len(typeKindNames) {
+		return typeKindNames[k]
+	}
+	return fmt.Sprintf("TypeKind(%d)", uint8(k))
+}
+
+// TypeInfo describes a type definition uniformly, see Introspectable.
+type TypeInfo struct {
+	Kind TypeKind
+	// Element type of lists, vectors and optionals. Nil for other kinds.
+	// Bitfields and byte strings have no element type definition.
+	Elem TypeDef
+	// Length of vectors, in elements (bits for bitvectors, bytes for byte vectors and small byte vectors).
+	Length uint64
+	// Limit of lists, in elements (bits for bitlists, bytes for byte lists),
+	// or the field capacity of a stable container. 0 for unbounded progressive lists.
+	Limit uint64
+	// Fields of containers, stable containers and profiles.
+	Fields []FieldDef
+	// Options of unions. The first option may be nil.
+	Options []TypeDef
+	// The number of chunks the contents are merkleized as. The limit for lists.
+	// 0 for unions, optionals and progressive lists, which are not merkleized as a single series of chunks.
+	ChunkCount uint64
+	// The depth of the contents in the backing tree, including the length mix-in of lists,
+	// the selector of unions and optionals, and the active fields of stable containers.
+	// 0 for basic types and progressive lists.
+	Depth uint8
+}
+
+// Introspectable is implemented by all type definitions of this package.
+type Introspectable interface {
+	TypeDef
+	Info() TypeInfo
+}
+
+// InfoOf describes the given type. The kind is KindUnknown if the type does not implement Introspectable.
+func InfoOf(t TypeDef) TypeInfo {
+	if x, ok := t.(Introspectable); ok {
+		return x.Info()
+	}
+	return TypeInfo{Kind: KindUnknown}
+}
+
+func (td UintMeta) Info() TypeInfo {
+	return TypeInfo{Kind: KindUint, ChunkCount: 1}
+}
+
+func (td BoolMeta) Info() TypeInfo {
+	return TypeInfo{Kind: KindBool, ChunkCount: 1}
+}
+
+func (td RootMeta) Info() TypeInfo {
+	return TypeInfo{Kind: KindRoot, Length: 32, ChunkCount: 1}
+}
+
+func (td SmallByteVecMeta) Info() TypeInfo {
+	return TypeInfo{Kind: KindSmallByteVector, Length: uint64(td), ChunkCount: 1}
+}
+
+func (td *ContainerTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindContainer,
+		Fields:     td.Fields,
+		ChunkCount: td.FieldCount(),
+		Depth:      CoverDepth(td.FieldCount()),
+	}
+}
+
+func (td *StableContainerTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindStableContainer,
+		Limit:      td.Capacity,
+		Fields:     td.Fields,
+		ChunkCount: td.Capacity,
+		Depth:      CoverDepth(td.Capacity) + 1,
+	}
+}
+
+// Info describes the profile fields, see the Fields of the type for which fields are optional.
+func (td *ProfileTypeDef) Info() TypeInfo {
+	fields := make([]FieldDef, len(td.Fields), len(td.Fields))
+	for i, f := range td.Fields {
+		fields[i] = FieldDef{Name: f.Name, Type: f.Type}
+	}
+	return TypeInfo{
+		Kind:       KindProfile,
+		Limit:      td.Base.Capacity,
+		Fields:     fields,
+		ChunkCount: td.Base.Capacity,
+		Depth:      CoverDepth(td.Base.Capacity) + 1,
+	}
+}
+
+func (td *ComplexListTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindComplexList,
+		Elem:       td.ElemType,
+		Limit:      td.ListLimit,
+		ChunkCount: td.ListLimit,
+		Depth:      CoverDepth(td.ListLimit) + 1,
+	}
+}
+
+func (td *ComplexVectorTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindComplexVector,
+		Elem:       td.ElemType,
+		Length:     td.VectorLength,
+		ChunkCount: td.VectorLength,
+		Depth:      CoverDepth(td.VectorLength),
+	}
+}
+
+func (td *BasicListTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindBasicList,
+		Elem:       td.ElemType,
+		Limit:      td.ListLimit,
+		ChunkCount: td.BottomNodeLimit(),
+		Depth:      CoverDepth(td.BottomNodeLimit()) + 1,
+	}
+}
+
+func (td *BasicVectorTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindBasicVector,
+		Elem:       td.ElemType,
+		Length:     td.VectorLength,
+		ChunkCount: td.BottomNodeLength(),
+		Depth:      CoverDepth(td.BottomNodeLength()),
+	}
+}
+
+func (td *BasicProgressiveListTypeDef) Info() TypeInfo {
+	return TypeInfo{Kind: KindBasicProgressiveList, Elem: td.ElemType}
+}
+
+func (td *ComplexProgressiveListTypeDef) Info() TypeInfo {
+	return TypeInfo{Kind: KindComplexProgressiveList, Elem: td.ElemType}
+}
+
+func (td *BitListTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindBitList,
+		Limit:      td.BitLimit,
+		ChunkCount: td.BottomNodeLimit(),
+		Depth:      CoverDepth(td.BottomNodeLimit()) + 1,
+	}
+}
+
+func (td *BitVectorTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindBitVector,
+		Length:     td.BitLength,
+		ChunkCount: td.BottomNodeLength(),
+		Depth:      CoverDepth(td.BottomNodeLength()),
+	}
+}
+
+func (td *ByteListTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindByteList,
+		Limit:      td.ListLimit,
+		ChunkCount: td.BottomNodeLimit(),
+		Depth:      CoverDepth(td.BottomNodeLimit()) + 1,
+	}
+}
+
+func (td *ByteVectorTypeDef) Info() TypeInfo {
+	return TypeInfo{
+		Kind:       KindByteVector,
+		Length:     td.VectorLength,
+		ChunkCount: td.BottomNodeLength(),
+		Depth:      CoverDepth(td.BottomNodeLength()),
+	}
+}
+
+func (td *UnionTypeDef) Info() TypeInfo {
+	return TypeInfo{Kind: KindUnion, Options: td.Options, Depth: 1}
+}
+
+func (td *OptionalTypeDef) Info() TypeInfo {
+	return TypeInfo{Kind: KindOptional, Elem: td.ElemType, Depth: 1}
+}
+
+// TypeVisitor is called for each type visited by WalkType, with the depth of the type in the type graph.
+// The subtypes are only visited if descend is true.
+type TypeVisitor func(t TypeDef, info TypeInfo, depth int) (descend bool, err error)
+
+// WalkType visits the type and its subtypes, depth-first:
+// the element type, then the field types, then the union options (excluding a nil option).
+// Types that occur multiple times in the graph are visited every time.
+// The base of a profile is not visited, only the fields of the profile.
+func WalkType(t TypeDef, visit TypeVisitor) error {
+	return walkType(t, visit, 0)
+}
+
+func walkType(t TypeDef, visit TypeVisitor, depth int) error {
+	x, ok := t.(Introspectable)
+	if !ok {
+		return fmt.Errorf("type %s does not support introspection", t.String())
+	}
+	info := x.Info()
+	descend, err := visit(t, info, depth)
+	if err != nil || !descend {
+		return err
+	}
+	if info.Elem != nil {
+		if err := walkType(info.Elem, visit, depth+1); err != nil {
+			return fmt.Errorf("elem: %w", err)
+		}
+	}
+	for _, f := range info.Fields {
+		if err := walkType(f.Type, visit, depth+1); err != nil {
+			return fmt.Errorf("field %q: %w", f.Name, err)
+		}
+	}
+	for i, opt := range info.Options {
+		if opt == nil {
+			continue
+		}
+		if err := walkType(opt, visit, depth+1); err != nil {
+			return fmt.Errorf("option %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package view
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeInfo(t *testing.T) {
+	for _, tt := range testCases {
+		info := InfoOf(tt.value.Type())
+		if info.Kind == KindUnknown {
+			t.Errorf("%s: type %s has unknown kind", tt.name, tt.value.Type().String())
+		}
+	}
+	cases := []struct {
+		typ  TypeDef
+		info TypeInfo
+	}{
+		{Uint64Type, TypeInfo{Kind: KindUint, ChunkCount: 1}},
+		{BasicListType(Uint16Type, 100), TypeInfo{Kind: KindBasicList, Elem: Uint16Type, Limit: 100, ChunkCount: 7, Depth: 4}},
+		{VectorType(FixedTestStructType, 5), TypeInfo{Kind: KindComplexVector, Elem: FixedTestStructType, Length: 5, ChunkCount: 5, Depth: 3}},
+		{BitListType(1000), TypeInfo{Kind: KindBitList, Limit: 1000, ChunkCount: 4, Depth: 3}},
+		{ByteVectorType(48), TypeInfo{Kind: KindByteVector, Length: 48, ChunkCount: 2, Depth: 1}},
+		{ShapeType, TypeInfo{Kind: KindStableContainer, Limit: 4, Fields: ShapeType.Fields, ChunkCount: 4, Depth: 3}},
+	}
+	for _, c := range cases {
+		got := InfoOf(c.typ)
+		if got.Kind != c.info.Kind || got.Elem != c.info.Elem || got.Length != c.info.Length || got.Limit != c.info.Limit ||
+			len(got.Fields) != len(c.info.Fields) || got.ChunkCount != c.info.ChunkCount || got.Depth != c.info.Depth {
+			t.Errorf("%s: expected %+v, got %+v", c.typ.String(), c.info, got)
+		}
+	}
+}
+
+func TestWalkType(t *testing.T) {
+	var out []string
+	err := WalkType(ComplexTestStructType, func(t TypeDef, info TypeInfo, depth int) (bool, error) {
+		out = append(out, strings.Repeat(" ", depth)+info.Kind.String())
+		// do not descend into nested containers
+		return depth == 0 || info.Kind != KindContainer, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"container",
+		" uint",
+		" basic list",
+		"  uint",
+		" uint",
+		" basic list",
+		"  uint",
+		" container",
+		" complex vector",
+		"  container",
+		" complex vector",
+		"  container",
+	}
+	if got := strings.Join(out, "\n"); got != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected walk:\n%s", got)
+	}
+	out = out[:0]
+	err = WalkType(UnionType([]TypeDef{nil, Uint8Type, ListType(Uint8Type, 3)}), func(t TypeDef, info TypeInfo, depth int) (bool, error) {
+		out = append(out, strings.Repeat(" ", depth)+info.Kind.String())
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(out, ","); got != "union, uint, basic list,  uint" {
+		t.Fatalf("unexpected union walk: %s", got)
+	}
+}
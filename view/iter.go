@@ -3,6 +3,7 @@ package view
 import (
 	"fmt"
 	. "github.com/protolambda/ztyp/tree"
+	"math/bits"
 )
 
 func basicElemReadonlyIter(anchor Node, length uint64, depth uint8, elemType BasicTypeDef) ElemIter {
@@ -142,8 +143,136 @@ func nodeReadonlyIter(anchor Node, length uint64, depth uint8) NodeIter {
 	})
 }
 
+func checkIterRange(start uint64, end uint64, length uint64) error {
+	if start > end || end > length {
+		return fmt.Errorf("cannot iterate range [%d, %d) of %d elements", start, end, length)
+	}
+	return nil
+}
+
+// nodeRangeIter iterates over the bottom nodes [start, end) of the subtree, or from end-1 down to start if reverse.
+// Only the paths to the iterated nodes are traversed, skipped subtrees are not visited.
+func nodeRangeIter(anchor Node, depth uint8, start uint64, end uint64, reverse bool) NodeIter {
+	if limit := uint64(1) << depth; start > end || end > limit {
+		return ErrNodeIter{fmt.Errorf("cannot iterate range [%d, %d) of nodes in subtree of depth %d deep (limit %d)", start, end, depth, limit)}
+	}
+	// the nodes on the path to the previous bottom node, stack[0] is the anchor.
+	stack := make([]Node, int(depth)+1, int(depth)+1)
+	stack[0] = anchor
+	remaining := end - start
+	index := start
+	if reverse {
+		index = end - 1
+	}
+	prev := uint64(0)
+	first := true
+	return NodeIterFn(func() (chunk Node, ok bool, err error) {
+		// done yet?
+		if remaining == 0 {
+			return nil, false, nil
+		}
+		d := uint8(0)
+		if !first {
+			// The highest bit that differs from the previous index is where the paths split,
+			// the stack above that is shared.
+			d = depth - uint8(bits.Len64(prev^index))
+		}
+		for ; d < depth; d++ {
+			node := stack[d]
+			if (index>>(depth-1-d))&1 == 1 {
+				node, err = node.Right()
+			} else {
+				node, err = node.Left()
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			stack[d+1] = node
+		}
+		first = false
+		prev = index
+		remaining -= 1
+		if reverse {
+			index -= 1
+		} else {
+			index += 1
+		}
+		return stack[depth], true, nil
+	})
+}
+
+type packedIterFn func() (r *Root, sub uint64, ok bool, err error)
+
+// packedRangeIter iterates over the packed elements [start, end), or from end-1 down to start if reverse,
+// with perNode elements per bottom node. It returns the bottom node and index within the node of each element.
+func packedRangeIter(anchor Node, depth uint8, perNode uint64, start uint64, end uint64, reverse bool) packedIterFn {
+	if start > end {
+		return func() (*Root, uint64, bool, error) {
+			return nil, 0, false, fmt.Errorf("invalid range [%d, %d)", start, end)
+		}
+	}
+	nodeIter := nodeRangeIter(anchor, depth, start/perNode, (end+perNode-1)/perNode, reverse)
+	remaining := end - start
+	index := start
+	if reverse {
+		index = end - 1
+	}
+	var currentRoot *Root
+	// start with a bottom node that can never match
+	currentNode := ^uint64(0)
+	return func() (r *Root, sub uint64, ok bool, err error) {
+		if remaining == 0 {
+			return nil, 0, false, nil
+		}
+		if n := index / perNode; n != currentNode {
+			node, ok, err := nodeIter.Next()
+			if err != nil {
+				return nil, 0, false, err
+			}
+			if !ok {
+				return nil, 0, false, fmt.Errorf("unexpected end of bottom nodes at element %d", index)
+			}
+			root, isRoot := node.(*Root)
+			if !isRoot {
+				return nil, 0, false, fmt.Errorf("expected leaf node %d to be a Root type", n)
+			}
+			currentRoot, currentNode = root, n
+		}
+		sub = index % perNode
+		remaining -= 1
+		if reverse {
+			index -= 1
+		} else {
+			index += 1
+		}
+		return currentRoot, sub, true, nil
+	}
+}
+
+func basicElemRangeIter(anchor Node, depth uint8, elemType BasicTypeDef, start uint64, end uint64, reverse bool) ElemIter {
+	iter := packedRangeIter(anchor, depth, 32/elemType.TypeByteLength(), start, end, reverse)
+	return ElemIterFn(func() (elem View, ok bool, err error) {
+		r, sub, ok, err := iter()
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+		el, err := elemType.BasicViewFromBacking(r, uint8(sub))
+		if err != nil {
+			return nil, false, err
+		}
+		return el, true, nil
+	})
+}
+
+func elemRangeIter(anchor Node, depth uint8, elemType TypeDef, start uint64, end uint64, reverse bool) ElemIter {
+	return elemIterFromNodes(nodeRangeIter(anchor, depth, start, end, reverse), elemType)
+}
+
 func elemReadonlyIter(node Node, length uint64, depth uint8, elemType TypeDef) ElemIter {
-	nodeIter := nodeReadonlyIter(node, length, depth)
+	return elemIterFromNodes(nodeReadonlyIter(node, length, depth), elemType)
+}
+
+func elemIterFromNodes(nodeIter NodeIter, elemType TypeDef) ElemIter {
 	// Re-use a typed view by changing its backing each iteration step.
 	elemView := elemType.Default(nil)
 	return ElemIterFn(func() (elem View, ok bool, err error) {
@@ -68,3 +68,125 @@ func TestNodeReadonlyIter(t *testing.T) {
 		})
 	}
 }
+
+func TestRangeIter(t *testing.T) {
+	const n = 77
+	complexList := ComplexListType(RootType, 128).New()
+	basicList := BasicListType(Uint16Type, 128).New()
+	bitList := BitListType(1000).New()
+	complexVec := ComplexVectorType(RootType, n).New()
+	basicVec := BasicVectorType(Uint16Type, n).New()
+	bits := make([]bool, n)
+	for i := 0; i < n; i++ {
+		bits[i] = i%3 == 0 || i%7 == 0
+	}
+	bitVec, err := BitVectorType(n).FromBits(bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		r := &RootView{byte(i), 0xaa}
+		if err := complexList.Append(r); err != nil {
+			t.Fatal(err)
+		}
+		if err := complexVec.Set(uint64(i), r); err != nil {
+			t.Fatal(err)
+		}
+		if err := basicList.Append(Uint16View(i * 100)); err != nil {
+			t.Fatal(err)
+		}
+		if err := basicVec.Set(uint64(i), Uint16View(i*100)); err != nil {
+			t.Fatal(err)
+		}
+		if err := bitList.Append(BoolView(bits[i])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	type rangeIterable interface {
+		ReadonlyIterRange(start uint64, end uint64) ElemIter
+		ReadonlyIterReverse(start uint64, end uint64) ElemIter
+	}
+	elemViews := []struct {
+		name     string
+		v        rangeIterable
+		expected func(i uint64) View
+	}{
+		{"complex list", complexList, func(i uint64) View { return &RootView{byte(i), 0xaa} }},
+		{"complex vector", complexVec, func(i uint64) View { return &RootView{byte(i), 0xaa} }},
+		{"basic list", basicList, func(i uint64) View { return Uint16View(i * 100) }},
+		{"basic vector", basicVec, func(i uint64) View { return Uint16View(i * 100) }},
+	}
+	type bitRangeIterable interface {
+		ReadonlyIterRange(start uint64, end uint64) BitIter
+		ReadonlyIterReverse(start uint64, end uint64) BitIter
+	}
+	bitViews := []struct {
+		name string
+		v    bitRangeIterable
+	}{
+		{"bit list", bitList},
+		{"bit vector", bitVec},
+	}
+	ranges := [][2]uint64{{0, n}, {0, 0}, {5, 6}, {15, 17}, {16, 64}, {3, 70}, {76, 77}}
+	for _, r := range ranges {
+		start, end := r[0], r[1]
+		for _, ev := range elemViews {
+			for _, reverse := range []bool{false, true} {
+				var iter ElemIter
+				if reverse {
+					iter = ev.v.ReadonlyIterReverse(start, end)
+				} else {
+					iter = ev.v.ReadonlyIterRange(start, end)
+				}
+				for k := uint64(0); k < end-start; k++ {
+					i := start + k
+					if reverse {
+						i = end - 1 - k
+					}
+					el, ok, err := iter.Next()
+					if err != nil || !ok {
+						t.Fatalf("%s [%d, %d) reverse %v: missing element %d: %v", ev.name, start, end, reverse, i, err)
+					}
+					if el.HashTreeRoot(GetHashFn()) != ev.expected(i).HashTreeRoot(GetHashFn()) {
+						t.Fatalf("%s [%d, %d) reverse %v: element %d does not match", ev.name, start, end, reverse, i)
+					}
+				}
+				if _, ok, err := iter.Next(); ok || err != nil {
+					t.Fatalf("%s [%d, %d) reverse %v: expected end: %v", ev.name, start, end, reverse, err)
+				}
+			}
+		}
+		for _, bv := range bitViews {
+			for _, reverse := range []bool{false, true} {
+				var iter BitIter
+				if reverse {
+					iter = bv.v.ReadonlyIterReverse(start, end)
+				} else {
+					iter = bv.v.ReadonlyIterRange(start, end)
+				}
+				for k := uint64(0); k < end-start; k++ {
+					i := start + k
+					if reverse {
+						i = end - 1 - k
+					}
+					b, ok, err := iter.Next()
+					if err != nil || !ok {
+						t.Fatalf("%s [%d, %d) reverse %v: missing bit %d: %v", bv.name, start, end, reverse, i, err)
+					}
+					if b != bits[i] {
+						t.Fatalf("%s [%d, %d) reverse %v: bit %d does not match", bv.name, start, end, reverse, i)
+					}
+				}
+				if _, ok, err := iter.Next(); ok || err != nil {
+					t.Fatalf("%s [%d, %d) reverse %v: expected end: %v", bv.name, start, end, reverse, err)
+				}
+			}
+		}
+	}
+	if _, _, err := complexList.ReadonlyIterRange(10, n+1).Next(); err == nil {
+		t.Fatal("expected error for range beyond length")
+	}
+	if _, _, err := bitVec.ReadonlyIterReverse(10, 9).Next(); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+}
@@ -0,0 +1,365 @@
+package view
+
+import (
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// Bulk modifications of lists: the affected subtrees are built in a single pass,
+// and the length mix-in is updated once.
+
+// subtreeReplaceRange replaces the bottom nodes [from, from+len(nodes)) of the subtree.
+// Untouched subtrees are reused, fully replaced subtrees are built from the nodes directly.
+func subtreeReplaceRange(node Node, depth uint8, from uint64, nodes []Node) (Node, error) {
+	count := uint64(len(nodes))
+	if count == 0 {
+		return node, nil
+	}
+	if width := uint64(1) << depth; from+count > width {
+		return nil, fmt.Errorf("cannot replace %d nodes at %d in subtree of depth %d", count, from, depth)
+	} else if from == 0 && count == width {
+		return SubtreeFillToContents(nodes, depth)
+	}
+	pivot := uint64(1) << (depth - 1)
+	var left, right Node
+	if node.IsLeaf() {
+		// a zero subtree, expand it
+		left, right = &ZeroHashes[depth-1], &ZeroHashes[depth-1]
+	} else {
+		var err error
+		if left, err = node.Left(); err != nil {
+			return nil, err
+		}
+		if right, err = node.Right(); err != nil {
+			return nil, err
+		}
+	}
+	if from < pivot {
+		n := pivot - from
+		if n > count {
+			n = count
+		}
+		var err error
+		if left, err = subtreeReplaceRange(left, depth-1, from, nodes[:n]); err != nil {
+			return nil, err
+		}
+		nodes = nodes[n:]
+		from = pivot
+	}
+	if len(nodes) > 0 {
+		var err error
+		if right, err = subtreeReplaceRange(right, depth-1, from-pivot, nodes); err != nil {
+			return nil, err
+		}
+	}
+	return NewPairNode(left, right), nil
+}
+
+// subtreeClearFrom replaces the bottom nodes from index from onwards with zero nodes.
+func subtreeClearFrom(node Node, depth uint8, from uint64) (Node, error) {
+	if from == 0 {
+		return &ZeroHashes[depth], nil
+	}
+	if from >= uint64(1)<<depth || node.IsLeaf() {
+		return node, nil
+	}
+	pivot := uint64(1) << (depth - 1)
+	left, err := node.Left()
+	if err != nil {
+		return nil, err
+	}
+	right, err := node.Right()
+	if err != nil {
+		return nil, err
+	}
+	if from < pivot {
+		if left, err = subtreeClearFrom(left, depth-1, from); err != nil {
+			return nil, err
+		}
+		right = &ZeroHashes[depth-1]
+	} else {
+		if right, err = subtreeClearFrom(right, depth-1, from-pivot); err != nil {
+			return nil, err
+		}
+	}
+	return NewPairNode(left, right), nil
+}
+
+func getBottomRoot(contents Node, depth uint8, i uint64) (*Root, error) {
+	g, err := ToGindex64(i, depth)
+	if err != nil {
+		return nil, err
+	}
+	node, err := contents.Getter(g)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := node.(*Root)
+	if !ok {
+		return nil, fmt.Errorf("bottom node %d is not a root", i)
+	}
+	return r, nil
+}
+
+// packedReplaceRange replaces the elements [start, start+count) of packed contents, with perNode elements per bottom node.
+// The set function applies element k of the range to the given bottom node, at index sub within the node.
+func packedReplaceRange(contents Node, depth uint8, perNode uint64, length uint64, start uint64, count uint64,
+	set func(k uint64, base *Root, sub uint64) *Root) (Node, error) {
+	if count == 0 {
+		return contents, nil
+	}
+	end := start + count
+	firstNode, endNode := start/perNode, (end+perNode-1)/perNode
+	existingNodes := (length + perNode - 1) / perNode
+	roots := make([]*Root, endNode-firstNode)
+	for i := range roots {
+		roots[i] = &ZeroHashes[0]
+	}
+	// only the first and last bottom node may have existing elements that are not replaced
+	for _, i := range []uint64{firstNode, endNode - 1} {
+		if i < existingNodes {
+			r, err := getBottomRoot(contents, depth, i)
+			if err != nil {
+				return nil, err
+			}
+			roots[i-firstNode] = r
+		}
+	}
+	for k := uint64(0); k < count; k++ {
+		j := start + k
+		roots[j/perNode-firstNode] = set(k, roots[j/perNode-firstNode], j%perNode)
+	}
+	nodes := make([]Node, len(roots))
+	for i, r := range roots {
+		nodes[i] = r
+	}
+	return subtreeReplaceRange(contents, depth, firstNode, nodes)
+}
+
+// packedTruncate removes all packed elements from index newLength onwards.
+// The clear function zeroes the elements from index sub onwards in the given bottom node.
+func packedTruncate(contents Node, depth uint8, perNode uint64, newLength uint64,
+	clear func(base *Root, sub uint64) *Root) (Node, error) {
+	keepNodes := (newLength + perNode - 1) / perNode
+	contents, err := subtreeClearFrom(contents, depth, keepNodes)
+	if err != nil {
+		return nil, err
+	}
+	if sub := newLength % perNode; sub != 0 {
+		last := keepNodes - 1
+		r, err := getBottomRoot(contents, depth, last)
+		if err != nil {
+			return nil, err
+		}
+		return subtreeReplaceRange(contents, depth, last, []Node{clear(r, sub)})
+	}
+	return contents, nil
+}
+
+func checkReplaceRange(length uint64, limit uint64, start uint64, count uint64) error {
+	if start > length {
+		return fmt.Errorf("cannot replace elements at index %d, list only has %d elements", start, length)
+	}
+	if end := start + count; end < start || end > limit {
+		return fmt.Errorf("list length is %d and replacing %d elements at index %d would exceed the list limit %d", length, count, start, limit)
+	}
+	return nil
+}
+
+func checkTruncate(length uint64, newLength uint64) error {
+	if newLength > length {
+		return fmt.Errorf("cannot truncate list of length %d to greater length %d", length, newLength)
+	}
+	return nil
+}
+
+func listWithContents(contents Node, length uint64) Node {
+	return &PairNode{LeftChild: contents, RightChild: Uint64View(length).Backing()}
+}
+
+func maxUint64(a uint64, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// AppendMany appends all the given elements.
+func (tv *ComplexListView) AppendMany(views ...View) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	return tv.ReplaceRange(ll, views...)
+}
+
+// ReplaceRange overwrites the elements starting at index start with the given elements,
+// and extends the list if the range goes past the end of the list. Start may not be greater than the list length.
+func (tv *ComplexListView) ReplaceRange(start uint64, views ...View) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	count := uint64(len(views))
+	if err := checkReplaceRange(ll, tv.ListLimit, start, count); err != nil {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	nodes := make([]Node, count)
+	for i, v := range views {
+		nodes[i] = v.Backing()
+	}
+	// one less depth, ignore length mix-in
+	contents, err = subtreeReplaceRange(contents, tv.depth-1, start, nodes)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, maxUint64(ll, start+count)))
+}
+
+// Truncate removes all elements from index newLength onwards.
+func (tv *ComplexListView) Truncate(newLength uint64) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if err := checkTruncate(ll, newLength); err != nil || ll == newLength {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	contents, err = subtreeClearFrom(contents, tv.depth-1, newLength)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, newLength))
+}
+
+// AppendMany appends all the given elements.
+func (tv *BasicListView) AppendMany(views ...BasicView) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	return tv.ReplaceRange(ll, views...)
+}
+
+// ReplaceRange overwrites the elements starting at index start with the given elements,
+// and extends the list if the range goes past the end of the list. Start may not be greater than the list length.
+func (tv *BasicListView) ReplaceRange(start uint64, views ...BasicView) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	count := uint64(len(views))
+	if err := checkReplaceRange(ll, tv.ListLimit, start, count); err != nil {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	contents, err = packedReplaceRange(contents, tv.depth-1, tv.ElementsPerBottomNode(), ll, start, count,
+		func(k uint64, base *Root, sub uint64) *Root {
+			return views[k].BackingFromBase(base, uint8(sub))
+		})
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, maxUint64(ll, start+count)))
+}
+
+// Truncate removes all elements from index newLength onwards.
+func (tv *BasicListView) Truncate(newLength uint64) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if err := checkTruncate(ll, newLength); err != nil || ll == newLength {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	elemSize := tv.ElemType.TypeByteLength()
+	contents, err = packedTruncate(contents, tv.depth-1, tv.ElementsPerBottomNode(), newLength,
+		func(base *Root, sub uint64) *Root {
+			out := *base
+			for i := sub * elemSize; i < 32; i++ {
+				out[i] = 0
+			}
+			return &out
+		})
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, newLength))
+}
+
+// AppendMany appends all the given bits.
+func (tv *BitListView) AppendMany(bits ...BoolView) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	return tv.ReplaceRange(ll, bits...)
+}
+
+// ReplaceRange overwrites the bits starting at index start with the given bits,
+// and extends the list if the range goes past the end of the list. Start may not be greater than the list length.
+func (tv *BitListView) ReplaceRange(start uint64, bits ...BoolView) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	count := uint64(len(bits))
+	if err := checkReplaceRange(ll, tv.BitLimit, start, count); err != nil {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	contents, err = packedReplaceRange(contents, tv.depth-1, 256, ll, start, count,
+		func(k uint64, base *Root, sub uint64) *Root {
+			return bits[k].BackingFromBitfieldBase(base, uint8(sub))
+		})
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, maxUint64(ll, start+count)))
+}
+
+// Truncate removes all bits from index newLength onwards.
+func (tv *BitListView) Truncate(newLength uint64) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if err := checkTruncate(ll, newLength); err != nil || ll == newLength {
+		return err
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	contents, err = packedTruncate(contents, tv.depth-1, 256, newLength,
+		func(base *Root, sub uint64) *Root {
+			out := *base
+			out[sub>>3] &= byte(1<<(sub&7)) - 1
+			for i := (sub >> 3) + 1; i < 32; i++ {
+				out[i] = 0
+			}
+			return &out
+		})
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(listWithContents(contents, newLength))
+}
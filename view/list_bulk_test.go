@@ -0,0 +1,155 @@
+package view
+
+import (
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestComplexListBulk(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := ComplexListType(RootType, 64)
+	elem := func(i int) View {
+		return &RootView{byte(i), byte(i >> 8), 0xff}
+	}
+	var expected []View
+	check := func(list *ComplexListView) {
+		t.Helper()
+		// compare with single-element appends
+		exp := td.New()
+		for _, v := range expected {
+			if err := exp.Append(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if list.HashTreeRoot(hFn) != exp.HashTreeRoot(hFn) {
+			t.Fatalf("length %d: root does not match", len(expected))
+		}
+	}
+	list := td.New()
+	var batch []View
+	for i := 0; i < 13; i++ {
+		batch = append(batch, elem(i))
+	}
+	if err := list.AppendMany(batch...); err != nil {
+		t.Fatal(err)
+	}
+	expected = append(expected, batch...)
+	check(list)
+	// replace across the end of the list
+	batch = []View{elem(100), elem(101), elem(102), elem(103)}
+	if err := list.ReplaceRange(11, batch...); err != nil {
+		t.Fatal(err)
+	}
+	expected = append(expected[:11], batch...)
+	check(list)
+	// replace within the list
+	if err := list.ReplaceRange(2, elem(200), elem(201)); err != nil {
+		t.Fatal(err)
+	}
+	expected[2], expected[3] = elem(200), elem(201)
+	check(list)
+	for _, n := range []uint64{15, 9, 8, 1, 0} {
+		if err := list.Truncate(n); err != nil {
+			t.Fatal(err)
+		}
+		expected = expected[:n]
+		check(list)
+	}
+	if err := list.Truncate(1); err == nil {
+		t.Fatal("expected error for truncating to greater length")
+	}
+	if err := list.ReplaceRange(1, elem(0)); err == nil {
+		t.Fatal("expected error for replacing past the end")
+	}
+	if err := list.AppendMany(make([]View, 65)...); err == nil {
+		t.Fatal("expected error for exceeding limit")
+	}
+}
+
+func TestBasicListBulk(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BasicListType(Uint16Type, 100)
+	var expected []BasicView
+	check := func(list *BasicListView) {
+		t.Helper()
+		exp := td.New()
+		for _, v := range expected {
+			if err := exp.Append(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if list.HashTreeRoot(hFn) != exp.HashTreeRoot(hFn) {
+			t.Fatalf("length %d: root does not match", len(expected))
+		}
+	}
+	list := td.New()
+	for _, n := range []int{3, 20, 1, 0, 16} {
+		var batch []BasicView
+		for i := 0; i < n; i++ {
+			batch = append(batch, Uint16View(len(expected)+i+1))
+		}
+		if err := list.AppendMany(batch...); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, batch...)
+		check(list)
+	}
+	if err := list.ReplaceRange(15, Uint16View(0xaaaa), Uint16View(0xbbbb), Uint16View(0xcccc)); err != nil {
+		t.Fatal(err)
+	}
+	expected[15], expected[16], expected[17] = Uint16View(0xaaaa), Uint16View(0xbbbb), Uint16View(0xcccc)
+	check(list)
+	for _, n := range []uint64{33, 32, 17, 16, 3, 0} {
+		if err := list.Truncate(n); err != nil {
+			t.Fatal(err)
+		}
+		expected = expected[:n]
+		check(list)
+	}
+}
+
+func TestBitListBulk(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BitListType(1000)
+	var expected []bool
+	check := func(list *BitListView) {
+		t.Helper()
+		exp := td.New()
+		if len(expected) > 0 {
+			var err error
+			if exp, err = td.FromBits(expected); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if list.HashTreeRoot(hFn) != exp.HashTreeRoot(hFn) {
+			t.Fatalf("length %d: root does not match", len(expected))
+		}
+	}
+	list := td.New()
+	for _, n := range []int{5, 300, 1, 250} {
+		var batch []BoolView
+		for i := 0; i < n; i++ {
+			b := (len(expected)+i)%3 != 0
+			batch = append(batch, BoolView(b))
+			expected = append(expected, b)
+		}
+		if err := list.AppendMany(batch...); err != nil {
+			t.Fatal(err)
+		}
+		check(list)
+	}
+	if err := list.ReplaceRange(250, true, true, true, true, true, true, true, true, true, true); err != nil {
+		t.Fatal(err)
+	}
+	for i := 250; i < 260; i++ {
+		expected[i] = true
+	}
+	check(list)
+	for _, n := range []uint64{513, 300, 256, 255, 9, 8, 0} {
+		if err := list.Truncate(n); err != nil {
+			t.Fatal(err)
+		}
+		expected = expected[:n]
+		check(list)
+	}
+}
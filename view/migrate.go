@@ -0,0 +1,104 @@
+package view
+
+import (
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// FieldTransform converts the value of a field in the old container to a value for the new field type.
+// The old value is nil if the old container has no field with the same name.
+type FieldTransform func(old View) (View, error)
+
+// ContainerMigration upgrades container views from one type to another, e.g. at a fork.
+// Fields are matched by name:
+//   - fields with a transform are set to the output of the transform.
+//   - fields with the same type (see TypeEqual) re-use the old backing subtree, nothing is copied.
+//   - new fields are set to their default.
+//   - removed fields are dropped.
+type ContainerMigration struct {
+	From *ContainerTypeDef
+	To   *ContainerTypeDef
+	// index of each new field in the old container, -1 if it is a new field
+	oldIndices []int
+	transforms []FieldTransform
+}
+
+// MigrateContainerType prepares a migration between the two container types.
+// Transforms are keyed by the name of the field in the new container.
+// A field that exists in both containers, but with a different type, requires a transform.
+func MigrateContainerType(from *ContainerTypeDef, to *ContainerTypeDef, transforms map[string]FieldTransform) (*ContainerMigration, error) {
+	m := &ContainerMigration{
+		From:       from,
+		To:         to,
+		oldIndices: make([]int, len(to.Fields), len(to.Fields)),
+		transforms: make([]FieldTransform, len(to.Fields), len(to.Fields)),
+	}
+	used := 0
+	for i, f := range to.Fields {
+		oldIndex := -1
+		if j, ok := from.FieldIndex(f.Name); ok {
+			oldIndex = int(j)
+		}
+		m.oldIndices[i] = oldIndex
+		if fn, ok := transforms[f.Name]; ok {
+			m.transforms[i] = fn
+			used++
+			continue
+		}
+		if oldIndex >= 0 {
+			if oldType := from.Fields[oldIndex].Type; !TypeEqual(oldType, f.Type) {
+				return nil, fmt.Errorf("field %q changed type from %s to %s, but has no transform",
+					f.Name, oldType.String(), f.Type.String())
+			}
+		}
+	}
+	if used != len(transforms) {
+		return nil, fmt.Errorf("got %d transforms, but only %d match fields of %s", len(transforms), used, to.String())
+	}
+	return m, nil
+}
+
+// Migrate creates a view of the new container type from the old view. The old view is not modified.
+func (m *ContainerMigration) Migrate(v *ContainerView) (*ContainerView, error) {
+	if !TypeEqual(v.ContainerTypeDef, m.From) {
+		return nil, fmt.Errorf("cannot migrate %s, expected %s", v.Type().String(), m.From.String())
+	}
+	nodes := make([]Node, len(m.To.Fields), len(m.To.Fields))
+	for i, f := range m.To.Fields {
+		var old View
+		if oldIndex := m.oldIndices[i]; oldIndex >= 0 {
+			// no hook: changes by a transform to the old field must not propagate to the old view
+			node, err := v.GetNode(uint64(oldIndex))
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			old, err = m.From.Fields[oldIndex].Type.ViewFromBacking(node, nil)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+		}
+		if fn := m.transforms[i]; fn != nil {
+			out, err := fn(old)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			if out == nil {
+				return nil, fmt.Errorf("field %q: transform returned no value", f.Name)
+			}
+			if !TypeCompatible(out.Type(), f.Type) {
+				return nil, fmt.Errorf("field %q: transform returned %s, expected %s",
+					f.Name, out.Type().String(), f.Type.String())
+			}
+			nodes[i] = out.Backing()
+		} else if old != nil {
+			nodes[i] = old.Backing()
+		} else {
+			nodes[i] = f.Type.DefaultNode()
+		}
+	}
+	rootNode, err := SubtreeFillToContents(nodes, CoverDepth(m.To.FieldCount()))
+	if err != nil {
+		return nil, err
+	}
+	return AsContainer(m.To.ViewFromBacking(rootNode, nil))
+}
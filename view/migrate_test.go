@@ -0,0 +1,136 @@
+package view
+
+import (
+	. "github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestContainerMigration(t *testing.T) {
+	oldType := ContainerType("StateV1", []FieldDef{
+		{Name: "slot", Type: Uint64Type},
+		{Name: "balances", Type: BasicListType(Uint64Type, 1024)},
+		{Name: "flags", Type: Uint8Type},
+		{Name: "removed", Type: Uint32Type},
+	})
+	newType := ContainerType("StateV2", []FieldDef{
+		{Name: "slot", Type: Uint64Type},
+		{Name: "balances", Type: BasicListType(Uint64Type, 1024)},
+		{Name: "flags", Type: Uint16Type},
+		{Name: "added", Type: VarTestStructType},
+		{Name: "counter", Type: Uint64Type},
+	})
+	balances, err := BasicListType(Uint64Type, 1024).FromUint64s([]uint64{10, 20, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := oldType.FromFields(Uint64View(123), balances, Uint8View(7), Uint32View(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrateContainerType(oldType, newType, nil); err == nil {
+		t.Fatal("expected error for retyped field without transform")
+	}
+	if _, err := MigrateContainerType(oldType, newType, map[string]FieldTransform{
+		"flags":   func(old View) (View, error) { return Uint16View(0), nil },
+		"unknown": func(old View) (View, error) { return Uint16View(0), nil },
+	}); err == nil {
+		t.Fatal("expected error for transform of unknown field")
+	}
+
+	m, err := MigrateContainerType(oldType, newType, map[string]FieldTransform{
+		"flags": func(old View) (View, error) {
+			v, err := AsUint8(old, nil)
+			if err != nil {
+				return nil, err
+			}
+			return Uint16View(v) << 8, nil
+		},
+		"counter": func(old View) (View, error) {
+			if old != nil {
+				t.Fatal("expected no old value for new field")
+			}
+			return Uint64View(1), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upgraded, err := m.Migrate(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := newType.FromFields(Uint64View(123), balances, Uint16View(7<<8), VarTestStructType.New(), Uint64View(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eq, err := Equal(upgraded, expected); err != nil || !eq {
+		t.Fatalf("unexpected upgraded container: %v", err)
+	}
+	// unchanged fields share the old backing
+	oldBalances, err := old.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBalances, err := upgraded.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldBalances.Backing() != newBalances.Backing() {
+		t.Fatal("expected balances subtree to be re-used")
+	}
+	// modifying the upgraded view does not affect the old view
+	if err := upgraded.Set(0, Uint64View(124)); err != nil {
+		t.Fatal(err)
+	}
+	if slot, err := AsUint64(old.Get(0)); err != nil || slot != 123 {
+		t.Fatalf("old view was modified: %d, %v", slot, err)
+	}
+	if _, err := m.Migrate(newType.New()); err == nil {
+		t.Fatal("expected error for container of wrong type")
+	}
+}
+
+func TestContainerMigrationTransformModifiesOld(t *testing.T) {
+	listType := BasicListType(Uint64Type, 16)
+	fromType := ContainerType("From", []FieldDef{{Name: "values", Type: listType}})
+	toType := ContainerType("To", []FieldDef{{Name: "values", Type: BasicListType(Uint64Type, 32)}})
+	values, err := listType.FromUint64s([]uint64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := fromType.FromFields(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hFn := GetHashFn()
+	before := old.HashTreeRoot(hFn)
+	m, err := MigrateContainerType(fromType, toType, map[string]FieldTransform{
+		"values": func(old View) (View, error) {
+			list, err := AsBasicList(old, nil)
+			if err != nil {
+				return nil, err
+			}
+			if err := list.Append(Uint64View(2)); err != nil {
+				return nil, err
+			}
+			return BasicListType(Uint64Type, 32).FromUint64s([]uint64{1, 2})
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Migrate(old); err != nil {
+		t.Fatal(err)
+	}
+	if old.HashTreeRoot(hFn) != before {
+		t.Fatal("old view was modified by the transform")
+	}
+	values, err = AsBasicList(old.Get(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := values.Length(); err != nil || n != 1 {
+		t.Fatalf("expected old length 1, got %d, %v", n, err)
+	}
+}
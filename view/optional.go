@@ -0,0 +1,234 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	. "github.com/protolambda/ztyp/tree"
+	"reflect"
+)
+
+// OptionalTypeDef is an Optional[T] (EIP-6475): either None, or Some value of the element type.
+// None is encoded as empty bytes, and Some as a 0x01 byte followed by the value.
+// The hash-tree-root is the root of the value (zero for None), with the presence (0 or 1) mixed in.
+type OptionalTypeDef struct {
+	ElemType TypeDef
+	ComplexTypeBase
+}
+
+func OptionalType(elemType TypeDef) *OptionalTypeDef {
+	if elemType == nil {
+		panic("optional requires an element type")
+	}
+	return &OptionalTypeDef{
+		ElemType: elemType,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize: 0,
+			// Add the presence byte
+			MaxSize:     elemType.MaxByteLength() + 1,
+			Size:        0,
+			IsFixedSize: false,
+		},
+	}
+}
+
+// None creates an optional without value.
+func (td *OptionalTypeDef) None() *OptionalView {
+	return td.New()
+}
+
+// Some creates an optional with the given value. Does not check the view type.
+func (td *OptionalTypeDef) Some(v View) (*OptionalView, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cannot create %s with nil value, use None instead", td.String())
+	}
+	var selectorNode Root
+	selectorNode[0] = 1
+	conView, _ := td.ViewFromBacking(NewPairNode(v.Backing(), &selectorNode), nil)
+	return conView.(*OptionalView), nil
+}
+
+func (td *OptionalTypeDef) DefaultNode() Node {
+	return NewPairNode(new(Root), new(Root))
+}
+
+func (td *OptionalTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	return &OptionalView{
+		BackedView: BackedView{
+			ViewBase: ViewBase{
+				TypeDef: td,
+			},
+			Hook:        hook,
+			BackingNode: node,
+		},
+		OptionalTypeDef: td,
+	}, nil
+}
+
+func (td *OptionalTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *OptionalTypeDef) New() *OptionalView {
+	return td.Default(nil).(*OptionalView)
+}
+
+func (td *OptionalTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	scope := dr.Scope()
+	if scope == 0 {
+		return td.None(), nil
+	}
+	selector, err := dr.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if selector != 1 {
+		return nil, dr.Errorf("optional value must be prefixed with 0x01, got %d", selector)
+	}
+	start := dr.Position()
+	v, err := td.ElemType.Deserialize(dr)
+	if err != nil {
+		return nil, dr.WrapIndex(0, err)
+	}
+	if err := dr.CheckConsumed(start, scope-1); err != nil {
+		return nil, err
+	}
+	return td.Some(v)
+}
+
+func (td *OptionalTypeDef) String() string {
+	return fmt.Sprintf("Optional[%s]", td.ElemType.String())
+}
+
+type OptionalView struct {
+	BackedView
+	*OptionalTypeDef
+}
+
+func AsOptional(v View, err error) (*OptionalView, error) {
+	if err != nil {
+		return nil, err
+	}
+	c, ok := v.(*OptionalView)
+	if !ok {
+		return nil, fmt.Errorf("view is not an optional: %v", v)
+	}
+	return c, nil
+}
+
+func (tv *OptionalView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+// IsSome returns true if the optional has a value.
+func (tv *OptionalView) IsSome() (bool, error) {
+	selectorNode, err := tv.BackingNode.Right()
+	if err != nil {
+		return false, fmt.Errorf("optional selector could not be read: %v", err)
+	}
+	root, ok := selectorNode.(*Root)
+	if !ok {
+		return false, fmt.Errorf("expected Root node for optional selector, but got type %T", selectorNode)
+	}
+	for i := 1; i < 32; i++ {
+		if root[i] != 0 {
+			return false, fmt.Errorf("optional selector node has invalid value: %x", root[:])
+		}
+	}
+	if root[0] > 1 {
+		return false, fmt.Errorf("optional selector must be 0 or 1, got %d", root[0])
+	}
+	return root[0] == 1, nil
+}
+
+// Value returns the value, or nil if None.
+// Modifications of the value are propagated to the optional.
+func (tv *OptionalView) Value() (View, error) {
+	if some, err := tv.IsSome(); err != nil || !some {
+		return nil, err
+	}
+	content, err := tv.BackingNode.Left()
+	if err != nil {
+		return nil, fmt.Errorf("could not access optional content node: %v", err)
+	}
+	return tv.ElemType.ViewFromBacking(content, tv.setValueNode)
+}
+
+// Set changes the value. Does not check the view type. A nil value changes the optional to None.
+func (tv *OptionalView) Set(v View) error {
+	if v == nil {
+		return tv.SetBacking(tv.DefaultNode())
+	}
+	return tv.setValueNode(v.Backing())
+}
+
+func (tv *OptionalView) setValueNode(b Node) error {
+	var selectorNode Root
+	selectorNode[0] = 1
+	return tv.SetBacking(NewPairNode(b, &selectorNode))
+}
+
+func (tv *OptionalView) ValueByteLength() (uint64, error) {
+	v, err := tv.Value()
+	if err != nil || v == nil {
+		return 0, err
+	}
+	size, err := v.ValueByteLength()
+	// add 1 for the presence byte
+	return size + 1, err
+}
+
+func (tv *OptionalView) Serialize(w *codec.EncodingWriter) error {
+	v, err := tv.Value()
+	if err != nil || v == nil {
+		return err
+	}
+	if err := w.WriteByte(1); err != nil {
+		return fmt.Errorf("failed to write presence byte: %v", err)
+	}
+	return v.Serialize(w)
+}
+
+// MarshalJSON encodes None as null, and Some as the JSON of the value.
+func (tv *OptionalView) MarshalJSON() ([]byte, error) {
+	v, err := tv.Value()
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return []byte("null"), nil
+	}
+	m, ok := v.(json.Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support JSON encoding", tv.ElemType.String())
+	}
+	return m.MarshalJSON()
+}
+
+// UnmarshalJSON decodes null as None, and any other JSON as the value.
+// The element type must support JSON decoding.
+func (tv *OptionalView) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return tv.Set(nil)
+	}
+	v := tv.ElemType.Default(nil)
+	// basic views are values, decode into a pointer to a copy of the value
+	ptr := reflect.New(reflect.TypeOf(v))
+	ptr.Elem().Set(reflect.ValueOf(v))
+	if u, ok := ptr.Interface().(json.Unmarshaler); ok {
+		if err := u.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		return tv.Set(ptr.Elem().Interface().(View))
+	}
+	if u, ok := v.(json.Unmarshaler); ok {
+		if err := u.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		return tv.Set(v)
+	}
+	return fmt.Errorf("%s does not support JSON decoding", tv.ElemType.String())
+}
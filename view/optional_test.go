@@ -0,0 +1,108 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestOptionalSetValue(t *testing.T) {
+	hFn := tree.GetHashFn()
+	listType := BasicListType(Uint64Type, 8)
+	td := OptionalType(listType)
+	opt := td.New()
+	if some, err := opt.IsSome(); err != nil || some {
+		t.Fatalf("expected none: %v", err)
+	}
+	if err := opt.Set(listType.New()); err != nil {
+		t.Fatal(err)
+	}
+	v, err := AsBasicList(opt.Value())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// modifications of the value propagate to the optional
+	if err := v.Append(Uint64View(3)); err != nil {
+		t.Fatal(err)
+	}
+	expectedList, err := listType.FromElements(Uint64View(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := td.Some(expectedList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opt.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+		t.Fatal("optional root does not match after modifying the value")
+	}
+	if err := opt.Set(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opt.HashTreeRoot(hFn) != td.None().HashTreeRoot(hFn) {
+		t.Fatal("expected none root")
+	}
+}
+
+func TestOptionalDeserializeInvalid(t *testing.T) {
+	td := OptionalType(Uint64Type)
+	if _, err := td.Deserialize(codec.NewBytesDecodingReader([]byte{0x00, 1, 0, 0, 0, 0, 0, 0, 0})); err == nil {
+		t.Fatal("expected error for 0x00 prefix")
+	}
+	if _, err := td.Deserialize(codec.NewBytesDecodingReader([]byte{0x01})); err == nil {
+		t.Fatal("expected error for missing value")
+	}
+	if _, err := td.Deserialize(codec.NewBytesDecodingReader([]byte{0x01, 1, 0, 0, 0, 0, 0, 0, 0, 0})); err == nil {
+		t.Fatal("expected error for trailing bytes")
+	}
+	// the same in stream mode, where the scope is not known upfront
+	stream := codec.NewDecodingReader(bytes.NewReader([]byte{0x01, 1, 0, 0, 0, 0, 0, 0, 0, 0}), 10)
+	if _, err := td.Deserialize(stream); err == nil {
+		t.Fatal("expected error for trailing bytes in stream mode")
+	}
+}
+
+func TestOptionalJSON(t *testing.T) {
+	td := OptionalType(Uint64Type)
+	type wrapper struct {
+		A *OptionalView `json:"a"`
+	}
+	some, err := td.Some(Uint64View(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		v    *OptionalView
+		json string
+	}{
+		{td.None(), `{"a":null}`},
+		{some, `{"a":"42"}`},
+	} {
+		out, err := json.Marshal(wrapper{A: c.v})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != c.json {
+			t.Fatalf("expected %s, got %s", c.json, out)
+		}
+		decoded := wrapper{A: td.New()}
+		if err := json.Unmarshal(out, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.A == nil {
+			// encoding/json does not call UnmarshalJSON for null
+			decoded.A = td.None()
+		}
+		if a, b := decoded.A.HashTreeRoot(tree.GetHashFn()), c.v.HashTreeRoot(tree.GetHashFn()); a != b {
+			t.Fatalf("decoded %s does not match", c.json)
+		}
+	}
+	if err := some.UnmarshalJSON([]byte("null")); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := some.Value(); err != nil || v != nil {
+		t.Fatalf("expected none after null, got %v: %v", v, err)
+	}
+}
@@ -0,0 +1,137 @@
+package view
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// PathField is a path element to select a container field by name.
+func PathField(name string) codec.PathElem {
+	return codec.PathElem{Field: name}
+}
+
+// PathIndex is a path element to select an element, or a container field by index.
+func PathIndex(i uint64) codec.PathElem {
+	return codec.PathElem{Index: i}
+}
+
+// GetPath navigates the fields and elements of the given path, starting from v.
+// Container fields can be selected by name or index. For unions, the index must match the selector.
+// The value of an optional is at index 0, if it is not None.
+// See BytesView.Path for the equivalent on SSZ bytes.
+func GetPath(v View, path ...codec.PathElem) (View, error) {
+	for depth, p := range path {
+		var err error
+		if p.Field != "" {
+			v, err = getFieldByName(v, p.Field)
+		} else {
+			v, err = getIndex(v, p.Index)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("path %d: %w", depth, err)
+		}
+	}
+	return v, nil
+}
+
+func getFieldByName(v View, name string) (View, error) {
+	switch c := v.(type) {
+	case *ContainerView:
+		if i, ok := c.FieldIndex(name); ok {
+			return c.Get(i)
+		}
+	case *StableContainerView:
+		for i, f := range c.Fields {
+			if f.Name == name {
+				return c.Get(uint64(i))
+			}
+		}
+	case *ProfileView:
+		for i, f := range c.Fields {
+			if f.Name == name {
+				return c.Get(uint64(i))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cannot get field %q of non-container %s", name, v.Type().String())
+	}
+	return nil, fmt.Errorf("%s has no field %q", v.Type().String(), name)
+}
+
+func getIndex(v View, i uint64) (View, error) {
+	switch t := v.(type) {
+	case *ContainerView:
+		return t.Get(i)
+	case *StableContainerView:
+		return t.Get(i)
+	case *ProfileView:
+		return t.Get(i)
+	case *ComplexListView:
+		return t.Get(i)
+	case *ComplexVectorView:
+		return t.Get(i)
+	case *BasicListView:
+		return t.Get(i)
+	case *BasicProgressiveListView:
+		return t.Get(i)
+	case *ComplexProgressiveListView:
+		return t.Get(i)
+	case *BasicVectorView:
+		return t.Get(i)
+	case *BitListView:
+		return t.Get(i)
+	case *BitVectorView:
+		return t.Get(i)
+	case *ByteListView:
+		length, err := t.Length()
+		if err != nil {
+			return nil, err
+		}
+		if i >= length {
+			return nil, fmt.Errorf("index %d out of range, byte list length is %d", i, length)
+		}
+		return getByte(&t.SubtreeView, i)
+	case *ByteVectorView:
+		if i >= t.VectorLength {
+			return nil, fmt.Errorf("index %d out of range, byte vector length is %d", i, t.VectorLength)
+		}
+		return getByte(&t.SubtreeView, i)
+	case *UnionView:
+		selector, err := t.Selector()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(selector) != i {
+			return nil, fmt.Errorf("union selector is %d, not %d", selector, i)
+		}
+		return t.Value()
+	case *OptionalView:
+		if i != 0 {
+			return nil, fmt.Errorf("optional only has index 0, not %d", i)
+		}
+		value, err := t.Value()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, fmt.Errorf("cannot get value of %s, it is None", t.Type().String())
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("cannot get element %d of %s", i, v.Type().String())
+	}
+}
+
+// getByte reads byte i of the packed contents of a byte list or vector, without reading the other bytes.
+func getByte(stv *SubtreeView, i uint64) (View, error) {
+	node, err := stv.GetNode(i >> 5)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := node.(*Root)
+	if !ok {
+		return nil, fmt.Errorf("cannot read byte %d from node %v", i, node)
+	}
+	return Uint8View(r[i&31]), nil
+}
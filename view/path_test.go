@@ -0,0 +1,73 @@
+package view
+
+import (
+	"testing"
+)
+
+func TestGetPathBytes(t *testing.T) {
+	contents := make([]byte, 40)
+	for i := range contents {
+		contents[i] = byte(i + 1)
+	}
+	container := ContainerType("Bytes", []FieldDef{
+		{Name: "list", Type: ByteListType(64)},
+		{Name: "vector", Type: ByteVectorType(40)},
+	})
+	list, err := ByteListType(64).FromBytes(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vector, err := ByteVectorType(40).FromBytes(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := container.FromFields(list, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"list", "vector"} {
+		for _, i := range []uint64{0, 31, 32, 39} {
+			b, err := GetPath(v, PathField(field), PathIndex(i))
+			if err != nil {
+				t.Fatalf("%s[%d]: %v", field, i, err)
+			}
+			if b != Uint8View(contents[i]) {
+				t.Fatalf("%s[%d]: expected %d, got %v", field, i, contents[i], b)
+			}
+		}
+		if _, err := GetPath(v, PathField(field), PathIndex(40)); err == nil {
+			t.Fatalf("%s: expected out of range error", field)
+		}
+	}
+}
+
+func TestGetPathOptional(t *testing.T) {
+	container := ContainerType("WithOptional", []FieldDef{
+		{Name: "opt", Type: OptionalType(FixedTestStructType)},
+	})
+	v := container.New()
+	if _, err := GetPath(v, PathField("opt"), PathIndex(0)); err == nil {
+		t.Fatal("expected error for None")
+	}
+	inner, err := FixedTestStructType.FromFields(Uint8View(1), Uint64View(2), Uint32View(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	some, err := OptionalType(FixedTestStructType).Some(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Set(0, some); err != nil {
+		t.Fatal(err)
+	}
+	x, err := GetPath(v, PathField("opt"), PathIndex(0), PathField("B"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x != Uint64View(2) {
+		t.Fatalf("expected 2, got %v", x)
+	}
+	if _, err := GetPath(v, PathField("opt"), PathIndex(1)); err == nil {
+		t.Fatal("expected error for index other than 0")
+	}
+}
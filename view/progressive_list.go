@@ -0,0 +1,734 @@
+package view
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// ProgressiveListTypeDef is a list without limit, merkleized progressively (EIP-7916).
+// The SSZ encoding is the same as that of a regular list.
+type ProgressiveListTypeDef interface {
+	TypeDef
+	ElementType() TypeDef
+}
+
+func ProgressiveListType(elemType TypeDef) ProgressiveListTypeDef {
+	basicElemType, ok := elemType.(BasicTypeDef)
+	if ok {
+		return BasicProgressiveListType(basicElemType)
+	} else {
+		return ComplexProgressiveListType(elemType)
+	}
+}
+
+// saturating multiplication, for max byte lengths of types without limit
+func mulSaturated(a uint64, b uint64) uint64 {
+	if a != 0 && b > ^uint64(0)/a {
+		return ^uint64(0)
+	}
+	return a * b
+}
+
+func progressiveListDefaultNode() Node {
+	return &PairNode{LeftChild: &ZeroHashes[0], RightChild: &ZeroHashes[0]}
+}
+
+func progressiveListLength(backing Node) (uint64, error) {
+	v, err := backing.Getter(RightGindex)
+	if err != nil {
+		return 0, err
+	}
+	llBytes, ok := v.(*Root)
+	if !ok {
+		return 0, fmt.Errorf("cannot read node %v as list-length", v)
+	}
+	return binary.LittleEndian.Uint64(llBytes[:8]), nil
+}
+
+func progressiveListChunk(backing Node, chunkIndex uint64) (Node, error) {
+	g, err := ProgressiveGindex(chunkIndex)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := backing.Left()
+	if err != nil {
+		return nil, err
+	}
+	return contents.Getter(g)
+}
+
+// progressiveListSetChunk sets the chunk, and the length mix-in if length is not nil.
+// If the chunk is the first of its stage, the stage is created where necessary.
+func progressiveListSetChunk(backing Node, chunkIndex uint64, chunk Node, length *uint64) (Node, error) {
+	contents, err := backing.Left()
+	if err != nil {
+		return nil, err
+	}
+	stage, start := ProgressiveStage(chunkIndex)
+	if chunkIndex == start {
+		sg, err := ProgressiveStageGindex(stage)
+		if err != nil {
+			return nil, err
+		}
+		stageNode, err := contents.Getter(sg)
+		if err != nil {
+			return nil, err
+		}
+		if stageNode.IsLeaf() {
+			setStage, err := contents.Setter(sg, false)
+			if err != nil {
+				return nil, err
+			}
+			contents, err = setStage(NewPairNode(&ZeroHashes[0], ZeroNode(2*uint32(stage))))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	g, err := ProgressiveGindex(chunkIndex)
+	if err != nil {
+		return nil, err
+	}
+	setChunk, err := contents.Setter(g, true)
+	if err != nil {
+		return nil, err
+	}
+	if contents, err = setChunk(chunk); err != nil {
+		return nil, err
+	}
+	return progressiveListRebind(backing, contents, length)
+}
+
+// progressiveListClearChunk zeroes the chunk, and removes its stage if it was the first chunk of the stage.
+// The chunk must be the last chunk, all later stages must be empty.
+func progressiveListClearChunk(backing Node, chunkIndex uint64, length uint64) (Node, error) {
+	contents, err := backing.Left()
+	if err != nil {
+		return nil, err
+	}
+	stage, start := ProgressiveStage(chunkIndex)
+	var g Gindex64
+	if chunkIndex == start {
+		g, err = ProgressiveStageGindex(stage)
+	} else {
+		g, err = ProgressiveGindex(chunkIndex)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setter, err := contents.Setter(g, false)
+	if err != nil {
+		return nil, err
+	}
+	if contents, err = setter(&ZeroHashes[0]); err != nil {
+		return nil, err
+	}
+	return progressiveListRebind(backing, contents, &length)
+}
+
+func progressiveListRebind(backing Node, contents Node, length *uint64) (Node, error) {
+	if length == nil {
+		return backing.RebindLeft(contents)
+	}
+	newLength := &Root{}
+	binary.LittleEndian.PutUint64(newLength[:8], *length)
+	return NewPairNode(contents, newLength), nil
+}
+
+// progressiveReadonlyIter iterates over the elements of the progressive contents tree, stage by stage.
+func progressiveReadonlyIter(contents Node, length uint64, perChunk uint64,
+	stageIter func(anchor Node, length uint64, depth uint8) ElemIter) ElemIter {
+	var current ElemIter
+	node := contents
+	capacity := perChunk
+	depth := uint8(0)
+	remaining := length
+	return ElemIterFn(func() (elem View, ok bool, err error) {
+		for {
+			if current != nil {
+				elem, ok, err = current.Next()
+				if err != nil || ok {
+					return
+				}
+			}
+			if remaining == 0 {
+				return nil, false, nil
+			}
+			subtree, err := node.Right()
+			if err != nil {
+				return nil, false, err
+			}
+			if node, err = node.Left(); err != nil {
+				return nil, false, err
+			}
+			n := remaining
+			if n > capacity {
+				n = capacity
+			}
+			current = stageIter(subtree, n, depth)
+			remaining -= n
+			capacity <<= 2
+			depth += 2
+		}
+	})
+}
+
+type BasicProgressiveListTypeDef struct {
+	ElemType BasicTypeDef
+	ComplexTypeBase
+}
+
+func BasicProgressiveListType(elemType BasicTypeDef) *BasicProgressiveListTypeDef {
+	return &BasicProgressiveListTypeDef{
+		ElemType: elemType,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     0,
+			MaxSize:     ProgressiveMaxChunks * 32,
+			Size:        0,
+			IsFixedSize: false,
+		},
+	}
+}
+
+func (td *BasicProgressiveListTypeDef) FromElements(v ...BasicView) (*BasicProgressiveListView, error) {
+	chunks, err := td.ElemType.PackViews(v)
+	if err != nil {
+		return nil, err
+	}
+	contentsRootNode, err := ProgressiveFillToContents(chunks)
+	if err != nil {
+		return nil, err
+	}
+	rootNode := &PairNode{LeftChild: contentsRootNode, RightChild: Uint64View(len(v)).Backing()}
+	listView, _ := td.ViewFromBacking(rootNode, nil)
+	return listView.(*BasicProgressiveListView), nil
+}
+
+func (td *BasicProgressiveListTypeDef) ElementType() TypeDef {
+	return td.ElemType
+}
+
+func (td *BasicProgressiveListTypeDef) ElementsPerBottomNode() uint64 {
+	return 32 / td.ElemType.TypeByteLength()
+}
+
+func (td *BasicProgressiveListTypeDef) DefaultNode() Node {
+	return progressiveListDefaultNode()
+}
+
+func (td *BasicProgressiveListTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	return &BasicProgressiveListView{
+		BackedView: BackedView{
+			ViewBase: ViewBase{
+				TypeDef: td,
+			},
+			Hook:        hook,
+			BackingNode: node,
+		},
+		BasicProgressiveListTypeDef: td,
+	}, nil
+}
+
+func (td *BasicProgressiveListTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *BasicProgressiveListTypeDef) New() *BasicProgressiveListView {
+	return td.Default(nil).(*BasicProgressiveListView)
+}
+
+func (td *BasicProgressiveListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	scope := dr.Scope()
+	elemSize := td.ElemType.TypeByteLength()
+	if scope%elemSize != 0 {
+		return nil, dr.Errorf("scope %d does not align to elem size %d", scope, elemSize)
+	}
+	if scope > td.MaxSize {
+		return nil, dr.Errorf("scope %d is too big, need %d or less bytes", scope, td.MaxSize)
+	}
+	length := scope / elemSize
+	if length == 0 {
+		return td.New(), nil
+	}
+	contents, err := dr.ReadBytes(scope)
+	if err != nil {
+		return nil, err
+	}
+	bottomNodes, err := BytesIntoNodes(contents)
+	if err != nil {
+		return nil, err
+	}
+	contentsRootNode, err := ProgressiveFillToContents(bottomNodes)
+	if err != nil {
+		return nil, err
+	}
+	rootNode := &PairNode{LeftChild: contentsRootNode, RightChild: Uint64View(length).Backing()}
+	listView, _ := td.ViewFromBacking(rootNode, nil)
+	return listView.(*BasicProgressiveListView), nil
+}
+
+func (td *BasicProgressiveListTypeDef) String() string {
+	return fmt.Sprintf("ProgressiveList[%s]", td.ElemType.String())
+}
+
+type BasicProgressiveListView struct {
+	BackedView
+	*BasicProgressiveListTypeDef
+}
+
+func AsBasicProgressiveList(v View, err error) (*BasicProgressiveListView, error) {
+	if err != nil {
+		return nil, err
+	}
+	bv, ok := v.(*BasicProgressiveListView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a basic progressive list: %v", v)
+	}
+	return bv, nil
+}
+
+func (tv *BasicProgressiveListView) Length() (uint64, error) {
+	return progressiveListLength(tv.BackingNode)
+}
+
+func (tv *BasicProgressiveListView) CheckIndex(i uint64) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if i >= ll {
+		return fmt.Errorf("cannot handle item at element index %d, list only has %d elements", i, ll)
+	}
+	return nil
+}
+
+func (tv *BasicProgressiveListView) subviewNode(i uint64) (r *Root, bottomIndex uint64, subIndex uint8, err error) {
+	perNode := tv.ElementsPerBottomNode()
+	bottomIndex, subIndex = i/perNode, uint8(i%perNode)
+	v, err := progressiveListChunk(tv.BackingNode, bottomIndex)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	r, ok := v.(*Root)
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("basic progressive list bottom node is not a root, at index %d", i)
+	}
+	return r, bottomIndex, subIndex, nil
+}
+
+func (tv *BasicProgressiveListView) Get(i uint64) (BasicView, error) {
+	if err := tv.CheckIndex(i); err != nil {
+		return nil, err
+	}
+	r, _, subIndex, err := tv.subviewNode(i)
+	if err != nil {
+		return nil, err
+	}
+	return tv.ElemType.BasicViewFromBacking(r, subIndex)
+}
+
+func (tv *BasicProgressiveListView) Set(i uint64, v BasicView) error {
+	if err := tv.CheckIndex(i); err != nil {
+		return err
+	}
+	r, bottomIndex, subIndex, err := tv.subviewNode(i)
+	if err != nil {
+		return err
+	}
+	bNode, err := progressiveListSetChunk(tv.BackingNode, bottomIndex, v.BackingFromBase(r, subIndex), nil)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *BasicProgressiveListView) Append(v BasicView) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	perNode := tv.ElementsPerBottomNode()
+	newLength := ll + 1
+	var chunk *Root
+	if ll%perNode == 0 {
+		// new bottom node
+		chunk = v.BackingFromBase(&ZeroHashes[0], 0)
+	} else {
+		// apply to existing partially zeroed bottom node
+		r, _, subIndex, err := tv.subviewNode(ll)
+		if err != nil {
+			return err
+		}
+		chunk = v.BackingFromBase(r, subIndex)
+	}
+	bNode, err := progressiveListSetChunk(tv.BackingNode, ll/perNode, chunk, &newLength)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *BasicProgressiveListView) Pop() error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if ll == 0 {
+		return fmt.Errorf("list length is 0 and no item can be popped")
+	}
+	r, bottomIndex, subIndex, err := tv.subviewNode(ll - 1)
+	if err != nil {
+		return err
+	}
+	newLength := ll - 1
+	if subIndex == 0 {
+		// last element in the bottom node, remove the node
+		bNode, err := progressiveListClearChunk(tv.BackingNode, bottomIndex, newLength)
+		if err != nil {
+			return err
+		}
+		return tv.SetBacking(bNode)
+	}
+	// Pop the item by setting it to the default
+	defaultElement, err := tv.ElemType.BasicViewFromBacking(&ZeroHashes[0], subIndex)
+	if err != nil {
+		return err
+	}
+	bNode, err := progressiveListSetChunk(tv.BackingNode, bottomIndex, defaultElement.BackingFromBase(r, subIndex), &newLength)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *BasicProgressiveListView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+func (tv *BasicProgressiveListView) Iter() ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	i := uint64(0)
+	return ElemIterFn(func() (elem View, ok bool, err error) {
+		if i < length {
+			elem, err = tv.Get(i)
+			ok = true
+			i += 1
+			return
+		} else {
+			return nil, false, nil
+		}
+	})
+}
+
+func (tv *BasicProgressiveListView) ReadonlyIter() ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	return progressiveReadonlyIter(contents, length, tv.ElementsPerBottomNode(),
+		func(anchor Node, length uint64, depth uint8) ElemIter {
+			return basicElemReadonlyIter(anchor, length, depth, tv.ElemType)
+		})
+}
+
+func (tv *BasicProgressiveListView) ValueByteLength() (uint64, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return 0, err
+	}
+	return length * tv.ElemType.TypeByteLength(), nil
+}
+
+func (tv *BasicProgressiveListView) Serialize(w *codec.EncodingWriter) error {
+	length, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	contents := make([]byte, length*tv.ElemType.TypeByteLength())
+	node, err := tv.BackingNode.Left()
+	if err != nil {
+		return err
+	}
+	// copy the chunks of each stage
+	start := uint64(0)
+	capacity := uint64(1)
+	for depth := uint8(0); start*32 < uint64(len(contents)); depth += 2 {
+		subtree, err := node.Right()
+		if err != nil {
+			return err
+		}
+		if node, err = node.Left(); err != nil {
+			return err
+		}
+		end := (start + capacity) * 32
+		if end > uint64(len(contents)) {
+			end = uint64(len(contents))
+		}
+		nodeCount := (end - start*32 + 31) / 32
+		if err := SubtreeIntoBytes(subtree, depth, nodeCount, contents[start*32:end]); err != nil {
+			return err
+		}
+		start += capacity
+		capacity <<= 2
+	}
+	return w.Write(contents)
+}
+
+type ComplexProgressiveListTypeDef struct {
+	ElemType TypeDef
+	ComplexTypeBase
+}
+
+func ComplexProgressiveListType(elemType TypeDef) *ComplexProgressiveListTypeDef {
+	maxSize := uint64(0)
+	if elemType.IsFixedByteLength() {
+		maxSize = mulSaturated(ProgressiveMaxChunks, elemType.TypeByteLength())
+	} else {
+		maxSize = mulSaturated(ProgressiveMaxChunks, elemType.MaxByteLength()+OffsetByteLength)
+	}
+	return &ComplexProgressiveListTypeDef{
+		ElemType: elemType,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     0,
+			MaxSize:     maxSize,
+			Size:        0,
+			IsFixedSize: false,
+		},
+	}
+}
+
+func (td *ComplexProgressiveListTypeDef) FromElements(v ...View) (*ComplexProgressiveListView, error) {
+	nodes := make([]Node, len(v), len(v))
+	for i, el := range v {
+		nodes[i] = el.Backing()
+	}
+	contentsRootNode, err := ProgressiveFillToContents(nodes)
+	if err != nil {
+		return nil, err
+	}
+	rootNode := &PairNode{LeftChild: contentsRootNode, RightChild: Uint64View(len(v)).Backing()}
+	listView, _ := td.ViewFromBacking(rootNode, nil)
+	return listView.(*ComplexProgressiveListView), nil
+}
+
+func (td *ComplexProgressiveListTypeDef) ElementType() TypeDef {
+	return td.ElemType
+}
+
+func (td *ComplexProgressiveListTypeDef) DefaultNode() Node {
+	return progressiveListDefaultNode()
+}
+
+func (td *ComplexProgressiveListTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	return &ComplexProgressiveListView{
+		BackedView: BackedView{
+			ViewBase: ViewBase{
+				TypeDef: td,
+			},
+			Hook:        hook,
+			BackingNode: node,
+		},
+		ComplexProgressiveListTypeDef: td,
+	}, nil
+}
+
+func (td *ComplexProgressiveListTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *ComplexProgressiveListTypeDef) New() *ComplexProgressiveListView {
+	return td.Default(nil).(*ComplexProgressiveListView)
+}
+
+func (td *ComplexProgressiveListTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	elements, err := deserializeComplexListElems(td.ElemType, ProgressiveMaxChunks, dr)
+	if err != nil {
+		return nil, err
+	}
+	return td.FromElements(elements...)
+}
+
+func (td *ComplexProgressiveListTypeDef) String() string {
+	return fmt.Sprintf("ProgressiveList[%s]", td.ElemType.String())
+}
+
+type ComplexProgressiveListView struct {
+	BackedView
+	*ComplexProgressiveListTypeDef
+}
+
+func AsComplexProgressiveList(v View, err error) (*ComplexProgressiveListView, error) {
+	if err != nil {
+		return nil, err
+	}
+	c, ok := v.(*ComplexProgressiveListView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a progressive list: %v", v)
+	}
+	return c, nil
+}
+
+func (tv *ComplexProgressiveListView) Length() (uint64, error) {
+	return progressiveListLength(tv.BackingNode)
+}
+
+func (tv *ComplexProgressiveListView) CheckIndex(i uint64) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if i >= ll {
+		return fmt.Errorf("cannot handle item at element index %d, list only has %d elements", i, ll)
+	}
+	return nil
+}
+
+func (tv *ComplexProgressiveListView) Get(i uint64) (View, error) {
+	if err := tv.CheckIndex(i); err != nil {
+		return nil, err
+	}
+	v, err := progressiveListChunk(tv.BackingNode, i)
+	if err != nil {
+		return nil, err
+	}
+	return tv.ElemType.ViewFromBacking(v, tv.ItemHook(i))
+}
+
+func (tv *ComplexProgressiveListView) Set(i uint64, v View) error {
+	return tv.setNode(i, v.Backing())
+}
+
+func (tv *ComplexProgressiveListView) setNode(i uint64, b Node) error {
+	if err := tv.CheckIndex(i); err != nil {
+		return err
+	}
+	bNode, err := progressiveListSetChunk(tv.BackingNode, i, b, nil)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *ComplexProgressiveListView) ItemHook(i uint64) BackingHook {
+	return func(b Node) error {
+		return tv.setNode(i, b)
+	}
+}
+
+func (tv *ComplexProgressiveListView) Append(v View) error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	newLength := ll + 1
+	bNode, err := progressiveListSetChunk(tv.BackingNode, ll, v.Backing(), &newLength)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *ComplexProgressiveListView) Pop() error {
+	ll, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	if ll == 0 {
+		return fmt.Errorf("list length is 0 and no item can be popped")
+	}
+	bNode, err := progressiveListClearChunk(tv.BackingNode, ll-1, ll-1)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *ComplexProgressiveListView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+func (tv *ComplexProgressiveListView) Iter() ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	i := uint64(0)
+	return ElemIterFn(func() (elem View, ok bool, err error) {
+		if i < length {
+			elem, err = tv.Get(i)
+			ok = true
+			i += 1
+			return
+		} else {
+			return nil, false, nil
+		}
+	})
+}
+
+func (tv *ComplexProgressiveListView) ReadonlyIter() ElemIter {
+	length, err := tv.Length()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	contents, err := tv.BackingNode.Left()
+	if err != nil {
+		return ErrElemIter{err}
+	}
+	return progressiveReadonlyIter(contents, length, 1,
+		func(anchor Node, length uint64, depth uint8) ElemIter {
+			return elemReadonlyIter(anchor, length, depth, tv.ElemType)
+		})
+}
+
+func (tv *ComplexProgressiveListView) ValueByteLength() (uint64, error) {
+	length, err := tv.Length()
+	if err != nil {
+		return 0, err
+	}
+	if tv.ElemType.IsFixedByteLength() {
+		return length * tv.ElemType.TypeByteLength(), nil
+	}
+	size := length * OffsetByteLength
+	iter := tv.ReadonlyIter()
+	for {
+		elem, ok, err := iter.Next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		valSize, err := elem.ValueByteLength()
+		if err != nil {
+			return 0, err
+		}
+		size += valSize
+	}
+	return size, nil
+}
+
+func (tv *ComplexProgressiveListView) Serialize(w *codec.EncodingWriter) error {
+	if tv.ElemType.IsFixedByteLength() {
+		return serializeComplexFixElemSeries(tv.ReadonlyIter(), w)
+	}
+	length, err := tv.Length()
+	if err != nil {
+		return err
+	}
+	return serializeComplexVarElemSeries(length, tv.ReadonlyIter, w)
+}
@@ -0,0 +1,191 @@
+package view
+
+import (
+	"bytes"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestBasicProgressiveListAppendPop(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := BasicProgressiveListType(Uint64Type)
+	list := td.New()
+	var elems []BasicView
+	check := func() {
+		expected, err := td.FromElements(elems...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, b := list.HashTreeRoot(hFn), expected.HashTreeRoot(hFn); a != b {
+			t.Fatalf("length %d: root %s does not match root %s of list created from elements", len(elems), a, b)
+		}
+		htr := hFn.Mixin(hFn.ProgressiveChunksHTR(func(i uint64) tree.Root {
+			var out tree.Root
+			for j := uint64(0); j < 4 && i*4+j < uint64(len(elems)); j++ {
+				out = *elems[i*4+j].BackingFromBase(&out, uint8(j))
+			}
+			return out
+		}, (uint64(len(elems))+3)/4), uint64(len(elems)))
+		if a := list.HashTreeRoot(hFn); a != htr {
+			t.Fatalf("length %d: root %s does not match progressive merkleization %s", len(elems), a, htr)
+		}
+	}
+	// cross stage boundaries: 1, 5, 21 and 85 chunks
+	for i := 0; i < 400; i++ {
+		v := Uint64View(1000 + i)
+		if err := list.Append(v); err != nil {
+			t.Fatal(err)
+		}
+		elems = append(elems, v)
+		check()
+	}
+	if err := list.Set(123, Uint64View(42)); err != nil {
+		t.Fatal(err)
+	}
+	elems[123] = Uint64View(42)
+	check()
+	if v, err := list.Get(123); err != nil || v != Uint64View(42) {
+		t.Fatalf("expected 42, got %v: %v", v, err)
+	}
+	var buf bytes.Buffer
+	if err := list.Serialize(codec.NewEncodingWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := td.Deserialize(codec.NewBytesDecodingReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.HashTreeRoot(hFn) != list.HashTreeRoot(hFn) {
+		t.Fatal("decoded list does not match")
+	}
+	for len(elems) > 0 {
+		if err := list.Pop(); err != nil {
+			t.Fatal(err)
+		}
+		elems = elems[:len(elems)-1]
+		check()
+	}
+}
+
+func TestComplexProgressiveListAppendPop(t *testing.T) {
+	hFn := tree.GetHashFn()
+	td := ComplexProgressiveListType(VarTestStructType)
+	list := td.New()
+	var elems []View
+	check := func() {
+		htr := hFn.ProgressiveListHTR(func(i uint64) tree.HTR {
+			return elems[i]
+		}, uint64(len(elems)))
+		if a := list.HashTreeRoot(hFn); a != htr {
+			t.Fatalf("length %d: root %s does not match progressive merkleization %s", len(elems), a, htr)
+		}
+	}
+	for i := 0; i < 30; i++ {
+		items := make([]BasicView, i%3, i%3)
+		for j := range items {
+			items[j] = Uint16View(j)
+		}
+		itemList := BasicListType(Uint16Type, 1024).New()
+		for _, item := range items {
+			if err := itemList.Append(item); err != nil {
+				t.Fatal(err)
+			}
+		}
+		v, err := VarTestStructType.FromFields(Uint16View(i), itemList, Uint8View(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := list.Append(v); err != nil {
+			t.Fatal(err)
+		}
+		elems = append(elems, v)
+		check()
+	}
+	// the SSZ encoding is the same as that of a regular list
+	regular, err := ComplexListType(VarTestStructType, 64).FromElements(elems...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected, got bytes.Buffer
+	if err := regular.Serialize(codec.NewEncodingWriter(&expected)); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Serialize(codec.NewEncodingWriter(&got)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), got.Bytes()) {
+		t.Fatalf("encoding differs from regular list:\n%x\n%x", got.Bytes(), expected.Bytes())
+	}
+	decoded, err := td.Deserialize(codec.NewBytesDecodingReader(got.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.HashTreeRoot(hFn) != list.HashTreeRoot(hFn) {
+		t.Fatal("decoded list does not match")
+	}
+	for len(elems) > 0 {
+		if err := list.Pop(); err != nil {
+			t.Fatal(err)
+		}
+		elems = elems[:len(elems)-1]
+		check()
+	}
+}
+
+func TestProgressiveListRoots(t *testing.T) {
+	hFn := tree.GetHashFn()
+	// computed with the hash_tree_root pseudocode of EIP-7916: the lengths cross the stage boundaries
+	basicVectors := []struct {
+		length int
+		root   string
+	}{
+		{0, "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b"},
+		{1, "1fda3d2fcb3bd680a4640a9714ef33a5762719a1382c70866f1cda6aa9fef8e7"},
+		{2, "8f306548e40cf6424adc54ea4714a78d9e545647e789d45c5dfad3c70fcf9fe6"},
+		{5, "81cc772829051a9f693bf0e39211a1dd00ab65081117f244b3b4942a9966223c"},
+		{21, "2e54ec3756b4d6b79d6b450cd0b62ee18347db38cf1a41dbe2f937e9076774d8"},
+		{22, "e3662a133fe254cf0c76db939575efa3bb26b23b05fbae43be434d2ebe425de6"},
+		{85, "0253b12e2d5fbd50f57fc8beb89953350b691c4bcb70d6289954d03a22e04fb0"},
+		{88, "9b038e041d9d96fd7d2640ec962c957a6048038bdb76c818d3c78fcf34032af0"},
+	}
+	for _, v := range basicVectors {
+		elems := make([]BasicView, v.length, v.length)
+		for i := range elems {
+			elems[i] = Uint64View(1000 + i)
+		}
+		list, err := BasicProgressiveListType(Uint64Type).FromElements(elems...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := list.HashTreeRoot(hFn); got.String() != "0x"+v.root {
+			t.Errorf("ProgressiveList[uint64] of length %d: expected root 0x%s, got %s", v.length, v.root, got)
+		}
+	}
+	complexVectors := []struct {
+		length int
+		root   string
+	}{
+		{0, "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b"},
+		{1, "4560d5377fee3d8898b257a797747820c04462de33650ed9ef7367d1ed6fad7f"},
+		{2, "9ef722ae7078cbedf0f4ad993d92d1190b4fc5e6cfbab81955265a81b6fe94d7"},
+		{5, "b65559cbdf33246debca21bd16a460c804b61a6f2a23a96bb15e7bdfed495aa7"},
+		{21, "28074311ffdbedc4e3e266088e870e96721300e25fffd8ecd64f7492ae255b49"},
+		{22, "d111fe1b9ea5c23d2079dbc8a7a39bac44d1e5ea511b1a4e2e53f1898defaea4"},
+	}
+	for _, v := range complexVectors {
+		list := ComplexProgressiveListType(FixedTestStructType).New()
+		for i := 0; i < v.length; i++ {
+			elem, err := FixedTestStructType.FromFields(Uint8View(i), Uint64View(i*1000), Uint32View(i*7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := list.Append(elem); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := list.HashTreeRoot(hFn); got.String() != "0x"+v.root {
+			t.Errorf("ProgressiveList[FixedTestStruct] of length %d: expected root 0x%s, got %s", v.length, v.root, got)
+		}
+	}
+}
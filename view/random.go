@@ -0,0 +1,219 @@
+package view
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	"math/rand"
+)
+
+// RandomOptions configures the generation of random values, see RandomView.
+type RandomOptions struct {
+	// MaxLength caps the length of lists, in elements (bits for bitlists, bytes for byte lists).
+	// The length of each list is uniformly random between 0 and the minimum of MaxLength and the list limit.
+	MaxLength uint64
+}
+
+// DefaultRandomOptions are used when no options are given to RandomView or RandomBytes.
+var DefaultRandomOptions = RandomOptions{MaxLength: 16}
+
+// RandomView creates a random valid value of the given type.
+// The output is deterministic: the same type, options and RNG seed produce the same value.
+// The options may be nil, to use DefaultRandomOptions.
+func RandomView(t TypeDef, rng *rand.Rand, opts *RandomOptions) (View, error) {
+	if opts == nil {
+		opts = &DefaultRandomOptions
+	}
+	return randomView(t, rng, opts)
+}
+
+// RandomBytes creates the SSZ encoding of a random valid value of the given type, see RandomView.
+func RandomBytes(t TypeDef, rng *rand.Rand, opts *RandomOptions) ([]byte, error) {
+	v, err := RandomView(t, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+	return SerializeToBytes(v)
+}
+
+func randomLength(rng *rand.Rand, limit uint64, opts *RandomOptions) uint64 {
+	if limit > opts.MaxLength {
+		limit = opts.MaxLength
+	}
+	return uint64(rng.Int63n(int64(limit) + 1))
+}
+
+func randomBytes(rng *rand.Rand, n uint64) []byte {
+	out := make([]byte, n, n)
+	rng.Read(out)
+	return out
+}
+
+func randomBitSlice(rng *rand.Rand, n uint64) []bool {
+	out := make([]bool, n, n)
+	for i := range out {
+		out[i] = rng.Intn(2) == 1
+	}
+	return out
+}
+
+func randomElems(t TypeDef, n uint64, rng *rand.Rand, opts *RandomOptions) ([]View, error) {
+	out := make([]View, n, n)
+	for i := range out {
+		v, err := randomView(t, rng, opts)
+		if err != nil {
+			return nil, fmt.Errorf("elem %d: %w", i, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func randomBasicElems(t BasicTypeDef, n uint64, rng *rand.Rand, opts *RandomOptions) ([]BasicView, error) {
+	out := make([]BasicView, n, n)
+	for i := range out {
+		v, err := randomView(t, rng, opts)
+		if err != nil {
+			return nil, fmt.Errorf("elem %d: %w", i, err)
+		}
+		b, ok := v.(BasicView)
+		if !ok {
+			return nil, fmt.Errorf("elem %d: %s is not a basic view", i, t.String())
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+func randomFields(fields []FieldDef, rng *rand.Rand, opts *RandomOptions) ([]View, error) {
+	out := make([]View, len(fields), len(fields))
+	for i, f := range fields {
+		v, err := randomView(f.Type, rng, opts)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func randomView(t TypeDef, rng *rand.Rand, opts *RandomOptions) (View, error) {
+	switch td := t.(type) {
+	case BoolMeta:
+		return BoolView(rng.Intn(2) == 1), nil
+	case UintMeta, RootMeta, SmallByteVecMeta:
+		// any bytes are valid
+		return td.Deserialize(codec.NewBytesDecodingReader(randomBytes(rng, td.TypeByteLength())))
+	case *ContainerTypeDef:
+		fields, err := randomFields(td.Fields, rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		return td.FromFields(fields...)
+	case *StableContainerTypeDef:
+		fields, err := randomFields(td.Fields, rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range fields {
+			if rng.Intn(2) == 0 {
+				fields[i] = nil
+			}
+		}
+		return td.FromFields(fields...)
+	case *ProfileTypeDef:
+		fields := make([]View, len(td.Fields), len(td.Fields))
+		for i, f := range td.Fields {
+			if f.Optional && rng.Intn(2) == 0 {
+				continue
+			}
+			v, err := randomView(f.Type, rng, opts)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			fields[i] = v
+		}
+		return td.FromFields(fields...)
+	case *ComplexListTypeDef:
+		elems, err := randomElems(td.ElemType, randomLength(rng, td.ListLimit, opts), rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return td.New(), nil
+		}
+		return td.FromElements(elems...)
+	case *ComplexVectorTypeDef:
+		elems, err := randomElems(td.ElemType, td.VectorLength, rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		return td.FromElements(elems...)
+	case *ComplexProgressiveListTypeDef:
+		elems, err := randomElems(td.ElemType, randomLength(rng, opts.MaxLength, opts), rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return td.New(), nil
+		}
+		return td.FromElements(elems...)
+	case *BasicListTypeDef:
+		elems, err := randomBasicElems(td.ElemType, randomLength(rng, td.ListLimit, opts), rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return td.New(), nil
+		}
+		return td.FromElements(elems...)
+	case *BasicVectorTypeDef:
+		elems, err := randomBasicElems(td.ElemType, td.VectorLength, rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		return td.FromElements(elems...)
+	case *BasicProgressiveListTypeDef:
+		elems, err := randomBasicElems(td.ElemType, randomLength(rng, opts.MaxLength, opts), rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return td.New(), nil
+		}
+		return td.FromElements(elems...)
+	case *BitListTypeDef:
+		bits := randomBitSlice(rng, randomLength(rng, td.BitLimit, opts))
+		if len(bits) == 0 {
+			return td.New(), nil
+		}
+		return td.FromBits(bits)
+	case *BitVectorTypeDef:
+		return td.FromBits(randomBitSlice(rng, td.BitLength))
+	case *ByteListTypeDef:
+		return td.FromBytes(randomBytes(rng, randomLength(rng, td.ListLimit, opts)))
+	case *ByteVectorTypeDef:
+		return td.FromBytes(randomBytes(rng, td.VectorLength))
+	case *UnionTypeDef:
+		selector := uint8(rng.Intn(len(td.Options)))
+		opt := td.Options[selector]
+		if opt == nil {
+			return td.FromView(selector, nil)
+		}
+		v, err := randomView(opt, rng, opts)
+		if err != nil {
+			return nil, fmt.Errorf("option %d: %w", selector, err)
+		}
+		return td.FromView(selector, v)
+	case *OptionalTypeDef:
+		if rng.Intn(2) == 0 {
+			return td.None(), nil
+		}
+		v, err := randomView(td.ElemType, rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		return td.Some(v)
+	default:
+		return nil, fmt.Errorf("cannot generate random value of unsupported type %s", t.String())
+	}
+}
@@ -0,0 +1,75 @@
+package view
+
+import (
+	"bytes"
+	"github.com/protolambda/ztyp/codec"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomView(t *testing.T) {
+	opts := &RandomOptions{MaxLength: 5}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := tt.value.Type()
+			for seed := int64(0); seed < 10; seed++ {
+				a, err := RandomBytes(typ, rand.New(rand.NewSource(seed)), opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := RandomBytes(typ, rand.New(rand.NewSource(seed)), opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(a, b) {
+					t.Fatalf("seed %d: output is not deterministic: %x <> %x", seed, a, b)
+				}
+				if err := ValidateBytes(typ, a); err != nil {
+					t.Fatalf("seed %d: invalid output %x: %v", seed, a, err)
+				}
+				decoded, err := typ.Deserialize(codec.NewBytesDecodingReader(a))
+				if err != nil {
+					t.Fatalf("seed %d: cannot decode output %x: %v", seed, a, err)
+				}
+				enc, err := SerializeToBytes(decoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(a, enc) {
+					t.Fatalf("seed %d: re-encoding %x does not match %x", seed, enc, a)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomViewLength(t *testing.T) {
+	td := BasicListType(Uint64Type, 1000)
+	seen := make(map[uint64]bool)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		v, err := AsBasicList(RandomView(td, rng, &RandomOptions{MaxLength: 3}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		length, err := v.Length()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if length > 3 {
+			t.Fatalf("length %d exceeds max length", length)
+		}
+		seen[length] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected all lengths 0-3, got %v", seen)
+	}
+	// the list limit is respected too
+	v, err := AsBitList(RandomView(BitListType(2), rng, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length, err := v.Length(); err != nil || length > 2 {
+		t.Fatalf("unexpected bitlist length %d: %v", length, err)
+	}
+}
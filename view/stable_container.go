@@ -0,0 +1,806 @@
+package view
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/codec"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// StableContainerTypeDef is a container with optional fields, and a fixed capacity N (EIP-7495),
+// so the gindices of the fields stay stable when fields are added in later versions.
+// The fields are merkleized like a container with N fields, absent fields have a zero root,
+// and the Bitvector[N] of active fields is mixed in.
+// The SSZ encoding is the active-fields bitvector, followed by the present fields, encoded like a container.
+type StableContainerTypeDef struct {
+	ContainerName    string
+	Fields           []FieldDef
+	Capacity         uint64
+	ActiveFieldsType *BitVectorTypeDef
+	ComplexTypeBase
+}
+
+func StableContainerType(name string, capacity uint64, fields []FieldDef) *StableContainerTypeDef {
+	if capacity == 0 {
+		panic("stable container requires a capacity of at least 1 field")
+	}
+	if uint64(len(fields)) > capacity {
+		panic(fmt.Errorf("stable container %s has %d fields, but a capacity of only %d", name, len(fields), capacity))
+	}
+	activeFieldsType := BitVectorType(capacity)
+	maxSize := activeFieldsType.TypeByteLength()
+	for _, f := range fields {
+		if f.Type.IsFixedByteLength() {
+			maxSize += f.Type.TypeByteLength()
+		} else {
+			maxSize += OffsetByteLength + f.Type.MaxByteLength()
+		}
+	}
+	return &StableContainerTypeDef{
+		ContainerName:    name,
+		Fields:           fields,
+		Capacity:         capacity,
+		ActiveFieldsType: activeFieldsType,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     activeFieldsType.TypeByteLength(),
+			MaxSize:     maxSize,
+			Size:        0,
+			IsFixedSize: false,
+		},
+	}
+}
+
+// FromFields creates a view with the given field values. Absent fields are nil.
+func (td *StableContainerTypeDef) FromFields(v ...View) (*StableContainerView, error) {
+	if len(td.Fields) != len(v) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(td.Fields), len(v))
+	}
+	rootNode, err := stableBacking(td.Capacity, td.ActiveFieldsType, v, nil)
+	if err != nil {
+		return nil, err
+	}
+	conView, _ := td.ViewFromBacking(rootNode, nil)
+	return conView.(*StableContainerView), nil
+}
+
+func (td *StableContainerTypeDef) FieldCount() uint64 {
+	return uint64(len(td.Fields))
+}
+
+func (td *StableContainerTypeDef) DefaultNode() Node {
+	// all fields absent
+	return &PairNode{LeftChild: &ZeroHashes[CoverDepth(td.Capacity)], RightChild: td.ActiveFieldsType.DefaultNode()}
+}
+
+func (td *StableContainerTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	return &StableContainerView{
+		SubtreeView: SubtreeView{
+			BackedView: BackedView{
+				ViewBase: ViewBase{
+					TypeDef: td,
+				},
+				Hook:        hook,
+				BackingNode: node,
+			},
+			depth: CoverDepth(td.Capacity) + 1, // +1 for active fields mix-in
+		},
+		StableContainerTypeDef: td,
+	}, nil
+}
+
+func (td *StableContainerTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *StableContainerTypeDef) New() *StableContainerView {
+	return td.Default(nil).(*StableContainerView)
+}
+
+func (td *StableContainerTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	if err := td.checkScope(dr.Scope()); err != nil {
+		return nil, dr.WrapErr(err)
+	}
+	active, err := readActiveBits(td.ActiveFieldsType, dr)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(td.Fields); i < len(active); i++ {
+		if active[i] {
+			return nil, dr.Errorf("field %d is active, but %s only has %d fields", i, td.ContainerName, len(td.Fields))
+		}
+	}
+	values, err := deserializePartialContainer(td.Fields, active, dr)
+	if err != nil {
+		return nil, err
+	}
+	return td.FromFields(values...)
+}
+
+func (td *StableContainerTypeDef) String() string {
+	return td.ContainerName
+}
+
+type StableContainerView struct {
+	SubtreeView
+	*StableContainerTypeDef
+}
+
+func AsStableContainer(v View, err error) (*StableContainerView, error) {
+	if err != nil {
+		return nil, err
+	}
+	c, ok := v.(*StableContainerView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a stable container: %v", v)
+	}
+	return c, nil
+}
+
+func (tv *StableContainerView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+// IsActive returns whether field i is present.
+func (tv *StableContainerView) IsActive(i uint64) (bool, error) {
+	if i >= uint64(len(tv.Fields)) {
+		return false, fmt.Errorf("cannot get field %d, %s only has %d fields", i, tv.ContainerName, len(tv.Fields))
+	}
+	return stableIsActive(tv.BackingNode, tv.ActiveFieldsType, i)
+}
+
+// ActiveFields returns a copy of the bitvector of active fields.
+func (tv *StableContainerView) ActiveFields() (*BitVectorView, error) {
+	return stableActiveFields(tv.BackingNode, tv.ActiveFieldsType)
+}
+
+// Get returns field i, or nil if the field is absent.
+func (tv *StableContainerView) Get(i uint64) (View, error) {
+	if active, err := tv.IsActive(i); err != nil || !active {
+		return nil, err
+	}
+	v, err := tv.SubtreeView.GetNode(i)
+	if err != nil {
+		return nil, err
+	}
+	return tv.Fields[i].Type.ViewFromBacking(v, tv.ItemHook(i))
+}
+
+// Set field i to v, or makes the field absent if v is nil.
+func (tv *StableContainerView) Set(i uint64, v View) error {
+	if i >= uint64(len(tv.Fields)) {
+		return fmt.Errorf("cannot set field %d, %s only has %d fields", i, tv.ContainerName, len(tv.Fields))
+	}
+	if v == nil {
+		return tv.setNode(i, nil)
+	}
+	return tv.setNode(i, v.Backing())
+}
+
+func (tv *StableContainerView) setNode(i uint64, b Node) error {
+	bNode, err := stableSetField(tv.BackingNode, tv.depth, tv.ActiveFieldsType, i, b)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *StableContainerView) ItemHook(i uint64) BackingHook {
+	return func(b Node) error {
+		return tv.setNode(i, b)
+	}
+}
+
+// FieldValues returns all field values, absent fields are nil.
+func (tv *StableContainerView) FieldValues() ([]View, error) {
+	values := make([]View, len(tv.Fields), len(tv.Fields))
+	for i := range tv.Fields {
+		v, err := tv.Get(uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (tv *StableContainerView) ValueByteLength() (uint64, error) {
+	values, err := tv.FieldValues()
+	if err != nil {
+		return 0, err
+	}
+	size, err := partialContainerByteLength(values)
+	if err != nil {
+		return 0, err
+	}
+	return tv.ActiveFieldsType.TypeByteLength() + size, nil
+}
+
+func (tv *StableContainerView) Serialize(w *codec.EncodingWriter) error {
+	active, err := tv.ActiveFields()
+	if err != nil {
+		return err
+	}
+	if err := active.Serialize(w); err != nil {
+		return err
+	}
+	values, err := tv.FieldValues()
+	if err != nil {
+		return err
+	}
+	return serializePartialContainer(values, w)
+}
+
+// ProfileFieldDef is a field of a Profile. The name must match a field of the base StableContainer.
+type ProfileFieldDef struct {
+	Name     string
+	Type     TypeDef
+	Optional bool
+}
+
+// ProfileTypeDef is a subset of the fields of a StableContainer (EIP-7495), where fields are either required or optional.
+// A Profile is merkleized exactly like its base StableContainer, and shares the same backing tree structure.
+// The SSZ encoding is a bitvector of the present optional fields (omitted if there are no optional fields),
+// followed by the present fields, encoded like a container.
+type ProfileTypeDef struct {
+	ProfileName string
+	Base        *StableContainerTypeDef
+	Fields      []ProfileFieldDef
+	// index of each profile field in the base StableContainer
+	BaseIndices []uint64
+	// the bitvector of optional fields that are present, nil if there are no optional fields
+	OptionalFieldsType *BitVectorTypeDef
+	ComplexTypeBase
+}
+
+func ProfileType(name string, base *StableContainerTypeDef, fields []ProfileFieldDef) *ProfileTypeDef {
+	baseIndices := make([]uint64, len(fields), len(fields))
+	optionalCount := uint64(0)
+	minSize := uint64(0)
+	maxSize := uint64(0)
+	j := 0
+	for i, f := range fields {
+		for j < len(base.Fields) && base.Fields[j].Name != f.Name {
+			j++
+		}
+		if j == len(base.Fields) {
+			panic(fmt.Errorf("profile %s field %d (%s) is not in base %s, or not in the same order", name, i, f.Name, base.ContainerName))
+		}
+		if bt := base.Fields[j].Type; !TypeEqual(f.Type, bt) {
+			panic(fmt.Errorf("profile %s field %s has type %s, but base %s has type %s", name, f.Name, f.Type.String(), base.ContainerName, bt.String()))
+		}
+		baseIndices[i] = uint64(j)
+		j++
+		var fieldMin, fieldMax uint64
+		if f.Type.IsFixedByteLength() {
+			fieldMin, fieldMax = f.Type.TypeByteLength(), f.Type.TypeByteLength()
+		} else {
+			fieldMin, fieldMax = OffsetByteLength+f.Type.MinByteLength(), OffsetByteLength+f.Type.MaxByteLength()
+		}
+		if f.Optional {
+			optionalCount += 1
+		} else {
+			minSize += fieldMin
+		}
+		maxSize += fieldMax
+	}
+	var optionalFieldsType *BitVectorTypeDef
+	if optionalCount > 0 {
+		optionalFieldsType = BitVectorType(optionalCount)
+		minSize += optionalFieldsType.TypeByteLength()
+		maxSize += optionalFieldsType.TypeByteLength()
+	}
+	size := uint64(0)
+	if minSize == maxSize {
+		size = minSize
+	}
+	return &ProfileTypeDef{
+		ProfileName:        name,
+		Base:               base,
+		Fields:             fields,
+		BaseIndices:        baseIndices,
+		OptionalFieldsType: optionalFieldsType,
+		ComplexTypeBase: ComplexTypeBase{
+			MinSize:     minSize,
+			MaxSize:     maxSize,
+			Size:        size,
+			IsFixedSize: minSize == maxSize,
+		},
+	}
+}
+
+// FromFields creates a view with the given field values. Absent optional fields are nil.
+func (td *ProfileTypeDef) FromFields(v ...View) (*ProfileView, error) {
+	if len(td.Fields) != len(v) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(td.Fields), len(v))
+	}
+	for i, f := range td.Fields {
+		if v[i] == nil && !f.Optional {
+			return nil, fmt.Errorf("profile %s field %s is required", td.ProfileName, f.Name)
+		}
+	}
+	rootNode, err := stableBacking(td.Base.Capacity, td.Base.ActiveFieldsType, v, td.BaseIndices)
+	if err != nil {
+		return nil, err
+	}
+	conView, _ := td.ViewFromBacking(rootNode, nil)
+	return conView.(*ProfileView), nil
+}
+
+// FromStableContainer converts a view of the base StableContainer into a Profile view, with the same backing.
+// Required fields must be present, and fields that are not part of the profile must be absent.
+func (td *ProfileTypeDef) FromStableContainer(v *StableContainerView) (*ProfileView, error) {
+	if v.StableContainerTypeDef != td.Base {
+		return nil, fmt.Errorf("cannot convert %s to profile %s of %s", v.ContainerName, td.ProfileName, td.Base.ContainerName)
+	}
+	j := 0
+	for i := range td.Base.Fields {
+		active, err := v.IsActive(uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		if j < len(td.Fields) && td.BaseIndices[j] == uint64(i) {
+			if !active && !td.Fields[j].Optional {
+				return nil, fmt.Errorf("profile %s field %s is required", td.ProfileName, td.Fields[j].Name)
+			}
+			j++
+		} else if active {
+			return nil, fmt.Errorf("field %s is not part of profile %s", td.Base.Fields[i].Name, td.ProfileName)
+		}
+	}
+	conView, _ := td.ViewFromBacking(v.BackingNode, nil)
+	return conView.(*ProfileView), nil
+}
+
+func (td *ProfileTypeDef) FieldCount() uint64 {
+	return uint64(len(td.Fields))
+}
+
+func (td *ProfileTypeDef) DefaultNode() Node {
+	// required fields are present with their default value, optional fields are absent
+	values := make([]View, len(td.Fields), len(td.Fields))
+	for i, f := range td.Fields {
+		if !f.Optional {
+			values[i] = f.Type.Default(nil)
+		}
+	}
+	// can ignore error, the fields all fit the base
+	rootNode, _ := stableBacking(td.Base.Capacity, td.Base.ActiveFieldsType, values, td.BaseIndices)
+	return rootNode
+}
+
+func (td *ProfileTypeDef) ViewFromBacking(node Node, hook BackingHook) (View, error) {
+	return &ProfileView{
+		SubtreeView: SubtreeView{
+			BackedView: BackedView{
+				ViewBase: ViewBase{
+					TypeDef: td,
+				},
+				Hook:        hook,
+				BackingNode: node,
+			},
+			depth: CoverDepth(td.Base.Capacity) + 1, // +1 for active fields mix-in
+		},
+		ProfileTypeDef: td,
+	}, nil
+}
+
+func (td *ProfileTypeDef) Default(hook BackingHook) View {
+	v, _ := td.ViewFromBacking(td.DefaultNode(), hook)
+	return v
+}
+
+func (td *ProfileTypeDef) New() *ProfileView {
+	return td.Default(nil).(*ProfileView)
+}
+
+func (td *ProfileTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
+	if err := td.checkScope(dr.Scope()); err != nil {
+		return nil, dr.WrapErr(err)
+	}
+	active := make([]bool, len(td.Fields), len(td.Fields))
+	var optionalActive []bool
+	if td.OptionalFieldsType != nil {
+		var err error
+		optionalActive, err = readActiveBits(td.OptionalFieldsType, dr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defs := make([]FieldDef, len(td.Fields), len(td.Fields))
+	j := 0
+	for i, f := range td.Fields {
+		defs[i] = FieldDef{Name: f.Name, Type: f.Type}
+		if f.Optional {
+			active[i] = optionalActive[j]
+			j++
+		} else {
+			active[i] = true
+		}
+	}
+	values, err := deserializePartialContainer(defs, active, dr)
+	if err != nil {
+		return nil, err
+	}
+	return td.FromFields(values...)
+}
+
+func (td *ProfileTypeDef) String() string {
+	return td.ProfileName
+}
+
+type ProfileView struct {
+	SubtreeView
+	*ProfileTypeDef
+}
+
+func AsProfile(v View, err error) (*ProfileView, error) {
+	if err != nil {
+		return nil, err
+	}
+	c, ok := v.(*ProfileView)
+	if !ok {
+		return nil, fmt.Errorf("view is not a profile: %v", v)
+	}
+	return c, nil
+}
+
+func (tv *ProfileView) Copy() (View, error) {
+	tvCopy := *tv
+	tvCopy.Hook = nil
+	return &tvCopy, nil
+}
+
+// ToStableContainer converts the profile into a view of the base StableContainer, with the same backing.
+func (tv *ProfileView) ToStableContainer() (*StableContainerView, error) {
+	return AsStableContainer(tv.Base.ViewFromBacking(tv.BackingNode, nil))
+}
+
+// IsActive returns whether field i of the profile is present.
+func (tv *ProfileView) IsActive(i uint64) (bool, error) {
+	if i >= uint64(len(tv.Fields)) {
+		return false, fmt.Errorf("cannot get field %d, %s only has %d fields", i, tv.ProfileName, len(tv.Fields))
+	}
+	return stableIsActive(tv.BackingNode, tv.Base.ActiveFieldsType, tv.BaseIndices[i])
+}
+
+// Get returns field i of the profile, or nil if the field is optional and absent.
+func (tv *ProfileView) Get(i uint64) (View, error) {
+	if active, err := tv.IsActive(i); err != nil || !active {
+		return nil, err
+	}
+	v, err := tv.SubtreeView.GetNode(tv.BaseIndices[i])
+	if err != nil {
+		return nil, err
+	}
+	return tv.Fields[i].Type.ViewFromBacking(v, tv.ItemHook(i))
+}
+
+// Set field i of the profile to v. Optional fields can be made absent with a nil v.
+func (tv *ProfileView) Set(i uint64, v View) error {
+	if i >= uint64(len(tv.Fields)) {
+		return fmt.Errorf("cannot set field %d, %s only has %d fields", i, tv.ProfileName, len(tv.Fields))
+	}
+	if v == nil {
+		if !tv.Fields[i].Optional {
+			return fmt.Errorf("profile %s field %s is required", tv.ProfileName, tv.Fields[i].Name)
+		}
+		return tv.setNode(i, nil)
+	}
+	return tv.setNode(i, v.Backing())
+}
+
+func (tv *ProfileView) setNode(i uint64, b Node) error {
+	bNode, err := stableSetField(tv.BackingNode, tv.depth, tv.Base.ActiveFieldsType, tv.BaseIndices[i], b)
+	if err != nil {
+		return err
+	}
+	return tv.SetBacking(bNode)
+}
+
+func (tv *ProfileView) ItemHook(i uint64) BackingHook {
+	return func(b Node) error {
+		return tv.setNode(i, b)
+	}
+}
+
+// FieldValues returns all field values of the profile, absent optional fields are nil.
+func (tv *ProfileView) FieldValues() ([]View, error) {
+	values := make([]View, len(tv.Fields), len(tv.Fields))
+	for i := range tv.Fields {
+		v, err := tv.Get(uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (tv *ProfileView) ValueByteLength() (uint64, error) {
+	values, err := tv.FieldValues()
+	if err != nil {
+		return 0, err
+	}
+	size, err := partialContainerByteLength(values)
+	if err != nil {
+		return 0, err
+	}
+	if tv.OptionalFieldsType != nil {
+		size += tv.OptionalFieldsType.TypeByteLength()
+	}
+	return size, nil
+}
+
+func (tv *ProfileView) Serialize(w *codec.EncodingWriter) error {
+	values, err := tv.FieldValues()
+	if err != nil {
+		return err
+	}
+	if tv.OptionalFieldsType != nil {
+		var bits []bool
+		for i, f := range tv.Fields {
+			if f.Optional {
+				bits = append(bits, values[i] != nil)
+			}
+		}
+		optional, err := tv.OptionalFieldsType.FromBits(bits)
+		if err != nil {
+			return err
+		}
+		if err := optional.Serialize(w); err != nil {
+			return err
+		}
+	}
+	return serializePartialContainer(values, w)
+}
+
+// stableBacking creates the backing of a stable container with the given capacity.
+// Nil values are absent. If indices is not nil, the values are placed at these field indices.
+func stableBacking(capacity uint64, activeFieldsType *BitVectorTypeDef, values []View, indices []uint64) (Node, error) {
+	nodes := make([]Node, 0, len(values))
+	active := make([]bool, capacity, capacity)
+	for i, v := range values {
+		index := uint64(i)
+		if indices != nil {
+			index = indices[i]
+		}
+		for uint64(len(nodes)) < index {
+			nodes = append(nodes, &ZeroHashes[0])
+		}
+		if v == nil {
+			nodes = append(nodes, &ZeroHashes[0])
+		} else {
+			nodes = append(nodes, v.Backing())
+			active[index] = true
+		}
+	}
+	depth := CoverDepth(capacity)
+	var fieldsNode Node = &ZeroHashes[depth]
+	if len(nodes) > 0 {
+		var err error
+		fieldsNode, err = SubtreeFillToContents(nodes, depth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	activeFields, err := activeFieldsType.FromBits(active)
+	if err != nil {
+		return nil, err
+	}
+	return &PairNode{LeftChild: fieldsNode, RightChild: activeFields.Backing()}, nil
+}
+
+func stableActiveFields(backing Node, activeFieldsType *BitVectorTypeDef) (*BitVectorView, error) {
+	node, err := backing.Right()
+	if err != nil {
+		return nil, err
+	}
+	return AsBitVector(activeFieldsType.ViewFromBacking(node, nil))
+}
+
+func stableIsActive(backing Node, activeFieldsType *BitVectorTypeDef, i uint64) (bool, error) {
+	activeFields, err := stableActiveFields(backing, activeFieldsType)
+	if err != nil {
+		return false, err
+	}
+	active, err := activeFields.Get(i)
+	return bool(active), err
+}
+
+// stableSetField sets the node of field i, and marks it as active. If the node is nil, the field is made absent.
+func stableSetField(backing Node, depth uint8, activeFieldsType *BitVectorTypeDef, i uint64, node Node) (Node, error) {
+	active := node != nil
+	if !active {
+		node = &ZeroHashes[0]
+	}
+	g, err := ToGindex64(i, depth)
+	if err != nil {
+		return nil, err
+	}
+	setField, err := backing.Setter(g, true)
+	if err != nil {
+		return nil, err
+	}
+	backing, err = setField(node)
+	if err != nil {
+		return nil, err
+	}
+	activeFields, err := stableActiveFields(backing, activeFieldsType)
+	if err != nil {
+		return nil, err
+	}
+	if err := activeFields.Set(i, BoolView(active)); err != nil {
+		return nil, err
+	}
+	return backing.RebindRight(activeFields.Backing())
+}
+
+func readActiveBits(td *BitVectorTypeDef, dr *codec.DecodingReader) ([]bool, error) {
+	sub, err := dr.SubScope(td.TypeByteLength())
+	if err != nil {
+		return nil, err
+	}
+	bitsView, err := AsBitVector(td.Deserialize(sub))
+	if err != nil {
+		return nil, err
+	}
+	dr.UpdateIndexFromScoped(sub)
+	bits := make([]bool, td.BitLength, td.BitLength)
+	for i := range bits {
+		b, err := bitsView.Get(uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = bool(b)
+	}
+	return bits, nil
+}
+
+// deserializePartialContainer decodes the active fields like a container, the other fields are nil.
+func deserializePartialContainer(fields []FieldDef, active []bool, dr *codec.DecodingReader) ([]View, error) {
+	values := make([]View, len(fields), len(fields))
+	fixedPartSize, minSize, maxSize := uint64(0), uint64(0), uint64(0)
+	for i, f := range fields {
+		if !active[i] {
+			continue
+		}
+		if f.Type.IsFixedByteLength() {
+			fixedPartSize += f.Type.TypeByteLength()
+			minSize += f.Type.TypeByteLength()
+			maxSize += f.Type.TypeByteLength()
+		} else {
+			fixedPartSize += OffsetByteLength
+			minSize += OffsetByteLength + f.Type.MinByteLength()
+			maxSize += OffsetByteLength + f.Type.MaxByteLength()
+		}
+	}
+	scope := dr.Scope()
+	if scope < minSize {
+		return nil, dr.Errorf("scope %d is too small for the present fields, need at least %d bytes", scope, minSize)
+	}
+	if scope > maxSize {
+		return nil, dr.Errorf("scope %d is too big for the present fields, need %d or less bytes", scope, maxSize)
+	}
+	offsets := make([]offsetField, 0)
+	prevOffset := uint32(fixedPartSize)
+	// Deserialize the fixed part: fixed-size fields and offsets to dynamic fields
+	for i, f := range fields {
+		if !active[i] {
+			continue
+		}
+		if f.Type.IsFixedByteLength() {
+			sub, err := dr.SubScope(f.Type.TypeByteLength())
+			if err != nil {
+				return nil, err
+			}
+			v, err := f.Type.Deserialize(sub)
+			if err != nil {
+				return nil, dr.WrapField(f.Name, err)
+			}
+			values[i] = v
+		} else {
+			offset, err := dr.ReadOffset()
+			if err != nil {
+				return nil, dr.WrapField(f.Name, err)
+			}
+			if len(offsets) == 0 && uint64(offset) != fixedPartSize {
+				return nil, dr.Errorf("first offset %d of field %d does not match fixed part size %d", offset, i, fixedPartSize)
+			}
+			if offset < prevOffset {
+				return nil, dr.Errorf("offset %d of field %d is smaller than prev offset %d", offset, i, prevOffset)
+			}
+			if uint64(offset) > scope {
+				return nil, dr.Errorf("offset %d of field %d is too big for scope %d", offset, i, scope)
+			}
+			prevOffset = offset
+			offsets = append(offsets, offsetField{index: i, offset: offset})
+		}
+	}
+	// Deserialize the dynamic part: for each offset, get the size and deserialize the element
+	for i, item := range offsets {
+		var size uint32
+		if i+1 == len(offsets) {
+			size = uint32(scope) - item.offset
+		} else {
+			size = offsets[i+1].offset - item.offset
+		}
+		sub, err := dr.SubScope(uint64(size))
+		if err != nil {
+			return nil, err
+		}
+		v, err := fields[item.index].Type.Deserialize(sub)
+		if err != nil {
+			return nil, dr.WrapField(fields[item.index].Name, err)
+		}
+		values[item.index] = v
+	}
+	return values, nil
+}
+
+// serializePartialContainer encodes the non-nil values like a container.
+func serializePartialContainer(values []View, w *codec.EncodingWriter) error {
+	fixedPartSize := uint64(0)
+	var dynFields []View
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if t := v.Type(); t.IsFixedByteLength() {
+			fixedPartSize += t.TypeByteLength()
+		} else {
+			fixedPartSize += OffsetByteLength
+		}
+	}
+	// the previous offset, to calculate a new offset from, starting after the fixed data.
+	prevOffset := fixedPartSize
+	// span of the previous var-size element
+	prevSize := uint64(0)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if v.Type().IsFixedByteLength() {
+			if err := v.Serialize(w); err != nil {
+				return err
+			}
+		} else {
+			size, err := v.ValueByteLength()
+			if err != nil {
+				return err
+			}
+			prevOffset, err = w.WriteOffset(prevOffset, prevSize)
+			if err != nil {
+				return err
+			}
+			prevSize = size
+			dynFields = append(dynFields, v)
+		}
+	}
+	for _, v := range dynFields {
+		if err := v.Serialize(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func partialContainerByteLength(values []View) (uint64, error) {
+	size := uint64(0)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if t := v.Type(); t.IsFixedByteLength() {
+			size += t.TypeByteLength()
+		} else {
+			vSize, err := v.ValueByteLength()
+			if err != nil {
+				return 0, err
+			}
+			size += OffsetByteLength + vSize
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,200 @@
+package view
+
+import (
+	"encoding/hex"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+// Shape types of the EIP-7495 examples
+var ShapeType = StableContainerType("Shape", 4, []FieldDef{
+	{"side", Uint16Type},
+	{"color", Uint8Type},
+	{"radius", Uint16Type},
+})
+
+var SquareType = ProfileType("Square", ShapeType, []ProfileFieldDef{
+	{Name: "side", Type: Uint16Type},
+	{Name: "color", Type: Uint8Type},
+})
+
+var CircleType = ProfileType("Circle", ShapeType, []ProfileFieldDef{
+	{Name: "color", Type: Uint8Type},
+	{Name: "radius", Type: Uint16Type, Optional: true},
+})
+
+func TestStableContainerSetGet(t *testing.T) {
+	hFn := tree.GetHashFn()
+	shape := ShapeType.New()
+	if shape.HashTreeRoot(hFn) != ShapeType.DefaultNode().MerkleRoot(hFn) {
+		t.Fatal("default root mismatch")
+	}
+	if v, err := shape.Get(0); err != nil || v != nil {
+		t.Fatalf("expected absent side, got %v: %v", v, err)
+	}
+	if err := shape.Set(0, Uint16View(0x42)); err != nil {
+		t.Fatal(err)
+	}
+	if err := shape.Set(1, Uint8View(1)); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ShapeType.FromFields(Uint16View(0x42), Uint8View(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+		t.Fatal("root does not match stable container created from fields")
+	}
+	// set, then unset the radius
+	if err := shape.Set(2, Uint16View(7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := shape.Set(2, nil); err != nil {
+		t.Fatal(err)
+	}
+	if shape.HashTreeRoot(hFn) != expected.HashTreeRoot(hFn) {
+		t.Fatal("root does not match after making field absent")
+	}
+	if err := shape.Set(3, Uint16View(1)); err == nil {
+		t.Fatal("expected error when setting field beyond field count")
+	}
+}
+
+func TestProfileConversion(t *testing.T) {
+	hFn := tree.GetHashFn()
+	square, err := SquareType.FromFields(Uint16View(0x42), Uint8View(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shape, err := square.ToStableContainer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape.HashTreeRoot(hFn) != square.HashTreeRoot(hFn) {
+		t.Fatal("profile root does not match stable container root")
+	}
+	back, err := SquareType.FromStableContainer(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.HashTreeRoot(hFn) != square.HashTreeRoot(hFn) {
+		t.Fatal("converted profile does not match")
+	}
+	if _, err := CircleType.FromStableContainer(shape); err == nil {
+		t.Fatal("expected error: side is not part of circle")
+	}
+	if err := square.Set(0, nil); err == nil {
+		t.Fatal("expected error: side is required")
+	}
+	circle := CircleType.New()
+	if v, err := circle.Get(1); err != nil || v != nil {
+		t.Fatalf("expected absent radius, got %v: %v", v, err)
+	}
+	if err := circle.Set(1, Uint16View(0x42)); err != nil {
+		t.Fatal(err)
+	}
+	shape, err = circle.ToStableContainer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := shape.Get(2); err != nil || v != Uint16View(0x42) {
+		t.Fatalf("expected radius 0x42, got %v: %v", v, err)
+	}
+	if _, err := SquareType.FromStableContainer(shape); err == nil {
+		t.Fatal("expected error: radius is not part of square, and side is required")
+	}
+}
+
+func TestStableContainerEIPExamples(t *testing.T) {
+	hFn := tree.GetHashFn()
+	square, err := SquareType.FromFields(Uint16View(0x42), Uint8View(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	circle, err := CircleType.FromFields(Uint8View(1), Uint16View(0x42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the encodings and roots of the EIP-7495 examples
+	cases := []struct {
+		name    string
+		profile *ProfileView
+		encoded string
+		shape   string
+		root    string
+	}{
+		{"square", square, "420001", "03420001", "bfdb6fda9d02805e640c0f5767b8d1bb9ff4211498a5e2d7c0f36e1b88ce57ff"},
+		{"circle", circle, "01014200", "06014200", "f66d2c38c8d2afbd409e86c529dff728e9a4208215ca20ee44e49c3d11e145d8"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := SerializeToBytes(c.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(out); got != c.encoded {
+				t.Errorf("expected encoding %s, got %s", c.encoded, got)
+			}
+			shape, err := c.profile.ToStableContainer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err = SerializeToBytes(shape)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(out); got != c.shape {
+				t.Errorf("expected stable container encoding %s, got %s", c.shape, got)
+			}
+			if got := c.profile.HashTreeRoot(hFn); got.String() != "0x"+c.root {
+				t.Errorf("expected root 0x%s, got %s", c.root, got)
+			}
+			if got := shape.HashTreeRoot(hFn); got.String() != "0x"+c.root {
+				t.Errorf("expected stable container root 0x%s, got %s", c.root, got)
+			}
+			data, err := hex.DecodeString(c.shape)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := ShapeType.Deserialize(codec.NewBytesDecodingReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := decoded.HashTreeRoot(hFn); got.String() != "0x"+c.root {
+				t.Errorf("expected decoded root 0x%s, got %s", c.root, got)
+			}
+		})
+	}
+}
+
+func TestProfileFieldTypeMismatch(t *testing.T) {
+	base := StableContainerType("Base", 4, []FieldDef{
+		{"a", ContainerType("Inner", []FieldDef{{"x", Uint16Type}})},
+	})
+	// a different definition of the same type is accepted
+	ProfileType("Valid", base, []ProfileFieldDef{{Name: "a", Type: ContainerType("Inner", []FieldDef{{"x", Uint16Type}})}})
+	// same type name, but a different structure
+	sameName := ContainerType("Inner", []FieldDef{{"x", Uint32Type}})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for mismatching field type")
+		}
+	}()
+	ProfileType("Invalid", base, []ProfileFieldDef{{Name: "a", Type: sameName}})
+}
+
+func TestStableContainerDeserializeInvalid(t *testing.T) {
+	// active bit for the 4th field, which does not exist
+	if _, err := ShapeType.Deserialize(codec.NewBytesDecodingReader([]byte{0x08, 0x00})); err == nil {
+		t.Fatal("expected error for unknown active field")
+	}
+	// no active fields, but trailing data
+	if _, err := ShapeType.Deserialize(codec.NewBytesDecodingReader([]byte{0x00, 0x00})); err == nil {
+		t.Fatal("expected error for trailing bytes")
+	}
+	// optional radius marked present, but missing
+	if _, err := CircleType.Deserialize(codec.NewBytesDecodingReader([]byte{0x01, 0x01})); err == nil {
+		t.Fatal("expected error for missing radius")
+	}
+}
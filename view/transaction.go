@@ -0,0 +1,128 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+var TransactionDoneError = errors.New("transaction is already committed or rolled back")
+
+// Savepoint identifies a backing recorded within a transaction, see Transaction.Savepoint.
+type Savepoint uint64
+
+type savepoint struct {
+	id      Savepoint
+	backing Node
+}
+
+// Transaction records the backing of a view, to undo changes to the view.
+// The view is modified as usual, directly, or through views attached to it with hooks.
+// Since nodes are immutable, recording and restoring a backing is cheap: nothing is copied.
+//
+// Restoring a backing sets it on the view, which propagates to the parent views through the hook of the view.
+// Views that were attached to the view before a rollback still have the backing of before the rollback,
+// and should be retrieved from the view again.
+type Transaction struct {
+	view       View
+	savepoints []savepoint
+	nextID     Savepoint
+	done       bool
+}
+
+// Begin starts a transaction on the view, recording its current backing.
+// The view must support SetBacking to roll back, basic views do not.
+func Begin(v View) *Transaction {
+	return &Transaction{
+		view:       v,
+		savepoints: []savepoint{{id: 0, backing: v.Backing()}},
+		nextID:     1,
+	}
+}
+
+// View returns the view of the transaction.
+func (tx *Transaction) View() View {
+	return tx.view
+}
+
+// Done returns true if the transaction was committed or rolled back.
+func (tx *Transaction) Done() bool {
+	return tx.done
+}
+
+// Changed returns true if the backing of the view is different from the backing at the start of the transaction.
+func (tx *Transaction) Changed() bool {
+	return tx.view.Backing() != tx.savepoints[0].backing
+}
+
+// Savepoint records the current backing of the view, to roll back to with RollbackTo.
+// Savepoints are nested: rolling back to, or releasing, a savepoint also discards all later savepoints.
+func (tx *Transaction) Savepoint() (Savepoint, error) {
+	if tx.done {
+		return 0, TransactionDoneError
+	}
+	id := tx.nextID
+	tx.nextID++
+	tx.savepoints = append(tx.savepoints, savepoint{id: id, backing: tx.view.Backing()})
+	return id, nil
+}
+
+func (tx *Transaction) savepointIndex(sp Savepoint) (int, error) {
+	if tx.done {
+		return 0, TransactionDoneError
+	}
+	// the first entry is the start of the transaction, not a savepoint
+	for i := len(tx.savepoints) - 1; i > 0; i-- {
+		if tx.savepoints[i].id == sp {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown savepoint %d, it may have been released or rolled back past", sp)
+}
+
+// RollbackTo restores the backing of the view to that of the savepoint.
+// The savepoint is kept, to roll back to it again, but later savepoints are discarded.
+func (tx *Transaction) RollbackTo(sp Savepoint) error {
+	i, err := tx.savepointIndex(sp)
+	if err != nil {
+		return err
+	}
+	if err := tx.view.SetBacking(tx.savepoints[i].backing); err != nil {
+		return fmt.Errorf("failed to roll back to savepoint %d: %w", sp, err)
+	}
+	tx.savepoints = tx.savepoints[:i+1]
+	return nil
+}
+
+// Release discards the savepoint and all later savepoints, keeping the changes made since.
+func (tx *Transaction) Release(sp Savepoint) error {
+	i, err := tx.savepointIndex(sp)
+	if err != nil {
+		return err
+	}
+	tx.savepoints = tx.savepoints[:i]
+	return nil
+}
+
+// Commit ends the transaction, keeping all changes.
+func (tx *Transaction) Commit() error {
+	if tx.done {
+		return TransactionDoneError
+	}
+	tx.done = true
+	tx.savepoints = tx.savepoints[:1]
+	return nil
+}
+
+// Rollback ends the transaction, restoring the backing of the view to that at the start of the transaction.
+func (tx *Transaction) Rollback() error {
+	if tx.done {
+		return TransactionDoneError
+	}
+	if err := tx.view.SetBacking(tx.savepoints[0].backing); err != nil {
+		return fmt.Errorf("failed to roll back transaction: %w", err)
+	}
+	tx.done = true
+	tx.savepoints = tx.savepoints[:1]
+	return nil
+}
@@ -0,0 +1,134 @@
+package view
+
+import (
+	"errors"
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestTransactionRollback(t *testing.T) {
+	hFn := tree.GetHashFn()
+	parent := ComplexTestStructType.New()
+	before := parent.HashTreeRoot(hFn)
+	// the transaction is on a field, changes propagate up to the parent through the hook
+	e, err := AsContainer(parent.Get(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := Begin(e)
+	if err := e.Set(0, Uint16View(1)); err != nil {
+		t.Fatal(err)
+	}
+	list, err := AsBasicList(e.Get(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Append(Uint16View(2)); err != nil {
+		t.Fatal(err)
+	}
+	if !tx.Changed() {
+		t.Fatal("expected change")
+	}
+	if parent.HashTreeRoot(hFn) == before {
+		t.Fatal("expected change of parent")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if parent.HashTreeRoot(hFn) != before {
+		t.Fatal("expected parent to be rolled back")
+	}
+	if tx.Changed() || !tx.Done() {
+		t.Fatal("expected rolled back transaction to be done, without changes")
+	}
+	if err := tx.Commit(); !errors.Is(err, TransactionDoneError) {
+		t.Fatalf("expected done error, got %v", err)
+	}
+	if _, err := tx.Savepoint(); !errors.Is(err, TransactionDoneError) {
+		t.Fatalf("expected done error, got %v", err)
+	}
+}
+
+func TestTransactionSavepoints(t *testing.T) {
+	list := BasicListType(Uint64Type, 16).New()
+	length := func() uint64 {
+		n, err := list.Length()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	tx := Begin(list)
+	appendN := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := list.Append(Uint64View(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	appendN(1)
+	sp1, err := tx.Savepoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(2)
+	sp2, err := tx.Savepoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(3)
+	if err := tx.RollbackTo(sp2); err != nil {
+		t.Fatal(err)
+	}
+	if n := length(); n != 3 {
+		t.Fatalf("expected length 3 at savepoint 2, got %d", n)
+	}
+	// savepoint 2 is kept after rolling back to it
+	appendN(1)
+	if err := tx.RollbackTo(sp2); err != nil {
+		t.Fatal(err)
+	}
+	if n := length(); n != 3 {
+		t.Fatalf("expected length 3 at savepoint 2, got %d", n)
+	}
+	// rolling back to savepoint 1 discards savepoint 2
+	if err := tx.RollbackTo(sp1); err != nil {
+		t.Fatal(err)
+	}
+	if n := length(); n != 1 {
+		t.Fatalf("expected length 1 at savepoint 1, got %d", n)
+	}
+	if err := tx.RollbackTo(sp2); err == nil {
+		t.Fatal("expected error for discarded savepoint")
+	}
+	// releasing keeps the changes
+	appendN(4)
+	if err := tx.Release(sp1); err != nil {
+		t.Fatal(err)
+	}
+	if n := length(); n != 5 {
+		t.Fatalf("expected length 5 after release, got %d", n)
+	}
+	if err := tx.RollbackTo(sp1); err == nil {
+		t.Fatal("expected error for released savepoint")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if n := length(); n != 5 {
+		t.Fatalf("expected length 5 after commit, got %d", n)
+	}
+	if err := tx.Rollback(); !errors.Is(err, TransactionDoneError) {
+		t.Fatalf("expected done error, got %v", err)
+	}
+}
+
+func TestTransactionBasicView(t *testing.T) {
+	tx := Begin(Uint64View(1))
+	if err := tx.Rollback(); !errors.Is(err, BasicViewNoSetBackingError) {
+		t.Fatalf("expected basic view error, got %v", err)
+	}
+	if tx.Done() {
+		t.Fatal("failed rollback must not end the transaction")
+	}
+}
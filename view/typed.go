@@ -0,0 +1,181 @@
+package view
+
+import "fmt"
+
+// Generic wrappers around the untyped list and vector views, to get and set elements of a concrete view type.
+// The untyped views remain accessible through the embedded field.
+
+// ElemIterOf iterates over elements of view type T.
+type ElemIterOf[T View] func() (elem T, ok bool, err error)
+
+func (f ElemIterOf[T]) Next() (elem T, ok bool, err error) {
+	return f()
+}
+
+// IterOf converts an untyped element iterator into a typed one.
+func IterOf[T View](iter ElemIter) ElemIterOf[T] {
+	return func() (elem T, ok bool, err error) {
+		v, ok, err := iter.Next()
+		if err != nil || !ok {
+			return elem, ok, err
+		}
+		elem, err = asElem[T](v, nil)
+		return elem, err == nil, err
+	}
+}
+
+func asElem[T View](v View, err error) (T, error) {
+	var out T
+	if err != nil {
+		return out, err
+	}
+	out, ok := v.(T)
+	if !ok {
+		return out, fmt.Errorf("element is not a %T: %v", out, v)
+	}
+	return out, nil
+}
+
+// ListView is a ComplexListView with elements of view type T.
+type ListView[T View] struct {
+	*ComplexListView
+}
+
+// NewListOf creates a new empty list of the given type, with elements of view type T.
+func NewListOf[T View](td *ComplexListTypeDef) *ListView[T] {
+	return &ListView[T]{td.New()}
+}
+
+func AsListOf[T View](v View, err error) (*ListView[T], error) {
+	list, err := AsComplexList(v, err)
+	if err != nil {
+		return nil, err
+	}
+	return &ListView[T]{list}, nil
+}
+
+func (tv *ListView[T]) Get(i uint64) (T, error) {
+	return asElem[T](tv.ComplexListView.Get(i))
+}
+
+func (tv *ListView[T]) Set(i uint64, v T) error {
+	return tv.ComplexListView.Set(i, v)
+}
+
+func (tv *ListView[T]) Append(v T) error {
+	return tv.ComplexListView.Append(v)
+}
+
+func (tv *ListView[T]) Iter() ElemIterOf[T] {
+	return IterOf[T](tv.ComplexListView.Iter())
+}
+
+func (tv *ListView[T]) ReadonlyIter() ElemIterOf[T] {
+	return IterOf[T](tv.ComplexListView.ReadonlyIter())
+}
+
+// VectorView is a ComplexVectorView with elements of view type T.
+type VectorView[T View] struct {
+	*ComplexVectorView
+}
+
+// NewVectorOf creates a new default vector of the given type, with elements of view type T.
+func NewVectorOf[T View](td *ComplexVectorTypeDef) *VectorView[T] {
+	return &VectorView[T]{td.New()}
+}
+
+func AsVectorOf[T View](v View, err error) (*VectorView[T], error) {
+	vec, err := AsComplexVector(v, err)
+	if err != nil {
+		return nil, err
+	}
+	return &VectorView[T]{vec}, nil
+}
+
+func (tv *VectorView[T]) Get(i uint64) (T, error) {
+	return asElem[T](tv.ComplexVectorView.Get(i))
+}
+
+func (tv *VectorView[T]) Set(i uint64, v T) error {
+	return tv.ComplexVectorView.Set(i, v)
+}
+
+func (tv *VectorView[T]) Iter() ElemIterOf[T] {
+	return IterOf[T](tv.ComplexVectorView.Iter())
+}
+
+func (tv *VectorView[T]) ReadonlyIter() ElemIterOf[T] {
+	return IterOf[T](tv.ComplexVectorView.ReadonlyIter())
+}
+
+// BasicListOf is a BasicListView with elements of basic view type T.
+type BasicListOf[T BasicView] struct {
+	*BasicListView
+}
+
+// NewBasicListOf creates a new empty list of the given type, with elements of basic view type T.
+func NewBasicListOf[T BasicView](td *BasicListTypeDef) *BasicListOf[T] {
+	return &BasicListOf[T]{td.New()}
+}
+
+func AsBasicListOf[T BasicView](v View, err error) (*BasicListOf[T], error) {
+	list, err := AsBasicList(v, err)
+	if err != nil {
+		return nil, err
+	}
+	return &BasicListOf[T]{list}, nil
+}
+
+func (tv *BasicListOf[T]) Get(i uint64) (T, error) {
+	return asElem[T](tv.BasicListView.Get(i))
+}
+
+func (tv *BasicListOf[T]) Set(i uint64, v T) error {
+	return tv.BasicListView.Set(i, v)
+}
+
+func (tv *BasicListOf[T]) Append(v T) error {
+	return tv.BasicListView.Append(v)
+}
+
+func (tv *BasicListOf[T]) Iter() ElemIterOf[T] {
+	return IterOf[T](tv.BasicListView.Iter())
+}
+
+func (tv *BasicListOf[T]) ReadonlyIter() ElemIterOf[T] {
+	return IterOf[T](tv.BasicListView.ReadonlyIter())
+}
+
+// BasicVectorOf is a BasicVectorView with elements of basic view type T.
+type BasicVectorOf[T BasicView] struct {
+	*BasicVectorView
+}
+
+// NewBasicVectorOf creates a new default vector of the given type, with elements of basic view type T.
+func NewBasicVectorOf[T BasicView](td *BasicVectorTypeDef) *BasicVectorOf[T] {
+	return &BasicVectorOf[T]{td.New()}
+}
+
+func AsBasicVectorOf[T BasicView](v View, err error) (*BasicVectorOf[T], error) {
+	vec, err := AsBasicVector(v, err)
+	if err != nil {
+		return nil, err
+	}
+	return &BasicVectorOf[T]{vec}, nil
+}
+
+func (tv *BasicVectorOf[T]) Get(i uint64) (T, error) {
+	return asElem[T](tv.BasicVectorView.Get(i))
+}
+
+func (tv *BasicVectorOf[T]) Set(i uint64, v T) error {
+	return tv.BasicVectorView.Set(i, v)
+}
+
+func (tv *BasicVectorOf[T]) Iter() ElemIterOf[T] {
+	return IterOf[T](tv.BasicVectorView.Iter())
+}
+
+func (tv *BasicVectorOf[T]) ReadonlyIter() ElemIterOf[T] {
+	return IterOf[T](tv.BasicVectorView.ReadonlyIter())
+}
@@ -0,0 +1,101 @@
+package view
+
+import (
+	"github.com/protolambda/ztyp/tree"
+	"testing"
+)
+
+func TestListOf(t *testing.T) {
+	hFn := tree.GetHashFn()
+	list := NewListOf[*ContainerView](ComplexListType(FixedTestStructType, 8))
+	for i := 0; i < 3; i++ {
+		if err := list.Append(FixedTestStructType.New()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	elem, err := list.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// typed elements are bound to the list like untyped elements
+	if err := elem.Set(0, Uint8View(0xab)); err != nil {
+		t.Fatal(err)
+	}
+	untyped, err := AsContainer(list.ComplexListView.Get(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if untyped.HashTreeRoot(hFn) != elem.HashTreeRoot(hFn) {
+		t.Fatal("modification of typed element did not propagate")
+	}
+	iter := list.ReadonlyIter()
+	count := 0
+	for {
+		el, ok, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		if el.ContainerTypeDef != FixedTestStructType {
+			t.Fatal("unexpected element type")
+		}
+		count++
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 elements, got %d", count)
+	}
+	// wrong element view type
+	wrong, err := AsListOf[*ComplexListView](list.ComplexListView, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Get(0); err == nil {
+		t.Fatal("expected error for wrong element type")
+	}
+}
+
+func TestBasicListOf(t *testing.T) {
+	list := NewBasicListOf[Uint64View](BasicListType(Uint64Type, 16))
+	for i := 0; i < 10; i++ {
+		if err := list.Append(Uint64View(i * 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := list.Set(3, 42); err != nil {
+		t.Fatal(err)
+	}
+	v, err := list.Get(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 42 {
+		t.Fatalf("expected 42, got %d", v)
+	}
+	sum := Uint64View(0)
+	iter := list.ReadonlyIter()
+	for {
+		el, ok, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		sum += el
+	}
+	if sum != 450-30+42 {
+		t.Fatalf("unexpected sum %d", sum)
+	}
+	vec, err := AsBasicVectorOf[Uint32View](BasicVectorType(Uint32Type, 4).New(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vec.Set(2, 7); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := vec.Get(2); err != nil || v != 7 {
+		t.Fatalf("expected 7, got %d: %v", v, err)
+	}
+}
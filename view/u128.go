@@ -0,0 +1,203 @@
+package view
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/holiman/uint256"
+	"github.com/protolambda/ztyp/codec"
+	"github.com/protolambda/ztyp/conv"
+	. "github.com/protolambda/ztyp/tree"
+	"math/big"
+	"strconv"
+)
+
+// Uint128View is a uint128, as two uint64 limbs. Limb 0 is the least significant, like in uint256.Int.
+type Uint128View [2]uint64
+
+func AsUint128(v View, err error) (Uint128View, error) {
+	if err != nil {
+		return Uint128View{}, err
+	}
+	n, ok := v.(Uint128View)
+	if !ok {
+		return Uint128View{}, fmt.Errorf("not a uint128 view: %v", v)
+	}
+	return n, nil
+}
+
+func (v Uint128View) SetBacking(b Node) error {
+	return BasicViewNoSetBackingError
+}
+
+// Bytes16 returns little endian encoding
+func (v Uint128View) Bytes16() (out [16]byte) {
+	binary.LittleEndian.PutUint64(out[0:8], v[0])
+	binary.LittleEndian.PutUint64(out[8:16], v[1])
+	return
+}
+
+// SetBytes16 sets view from little endian encoding
+func (v *Uint128View) SetBytes16(data [16]byte) {
+	v.setBytes16(data[:])
+}
+
+func (v *Uint128View) setBytes16(data []byte) {
+	v[0] = binary.LittleEndian.Uint64(data[0:8])
+	v[1] = binary.LittleEndian.Uint64(data[8:16])
+}
+
+// Bytes returns little endian encoding (always 16 bytes)
+func (v Uint128View) Bytes() []byte {
+	out := v.Bytes16()
+	return out[:]
+}
+
+func (v Uint128View) Backing() Node {
+	out := &Root{}
+	binary.LittleEndian.PutUint64(out[0:8], v[0])
+	binary.LittleEndian.PutUint64(out[8:16], v[1])
+	return out
+}
+
+func (v Uint128View) BackingFromBase(base *Root, i uint8) *Root {
+	if i >= 2 {
+		return nil
+	}
+	newRoot := *base
+	binary.LittleEndian.PutUint64(newRoot[i*16:i*16+8], v[0])
+	binary.LittleEndian.PutUint64(newRoot[i*16+8:i*16+16], v[1])
+	return &newRoot
+}
+
+func (v Uint128View) Copy() (View, error) {
+	return v, nil
+}
+
+func (v Uint128View) ValueByteLength() (uint64, error) {
+	return 16, nil
+}
+
+func (v Uint128View) ByteLength() uint64 {
+	return 16
+}
+
+func (v Uint128View) FixedLength() uint64 {
+	return 16
+}
+
+func (v Uint128View) Serialize(w *codec.EncodingWriter) error {
+	if err := w.WriteUint64(v[0]); err != nil {
+		return err
+	}
+	return w.WriteUint64(v[1])
+}
+
+func (v Uint128View) Encode() ([]byte, error) {
+	return v.Bytes(), nil
+}
+
+func (v *Uint128View) Deserialize(r *codec.DecodingReader) error {
+	var data [16]byte
+	if _, err := r.Read(data[:]); err != nil {
+		return err
+	}
+	v.SetBytes16(data)
+	return nil
+}
+
+func (v *Uint128View) Decode(x []byte) error {
+	if len(x) != 16 {
+		return BadLengthError
+	}
+	v.setBytes16(x)
+	return nil
+}
+
+func (v Uint128View) HashTreeRoot(h HashFn) Root {
+	newRoot := Root{}
+	binary.LittleEndian.PutUint64(newRoot[0:8], v[0])
+	binary.LittleEndian.PutUint64(newRoot[8:16], v[1])
+	return newRoot
+}
+
+func (v Uint128View) Type() TypeDef {
+	return Uint128Type
+}
+
+// ToBig returns the value as a new big.Int
+func (v Uint128View) ToBig() *big.Int {
+	x := new(big.Int).SetUint64(v[1])
+	x.Lsh(x, 64)
+	return x.Or(x, new(big.Int).SetUint64(v[0]))
+}
+
+// SetFromBig sets the view to x, and returns true if x is negative or does not fit in 128 bits.
+// The view is not changed in that case.
+func (v *Uint128View) SetFromBig(x *big.Int) (overflow bool) {
+	if x.Sign() < 0 || x.BitLen() > 128 {
+		return true
+	}
+	var tmp [16]byte
+	x.FillBytes(tmp[:])
+	v[0] = binary.BigEndian.Uint64(tmp[8:16])
+	v[1] = binary.BigEndian.Uint64(tmp[0:8])
+	return false
+}
+
+// ToUint256 returns the value as uint256
+func (v Uint128View) ToUint256() *uint256.Int {
+	return &uint256.Int{v[0], v[1], 0, 0}
+}
+
+// SetFromUint256 sets the view to x, and returns true if x does not fit in 128 bits.
+// The view is not changed in that case.
+func (v *Uint128View) SetFromUint256(x *uint256.Int) (overflow bool) {
+	if x[2] != 0 || x[3] != 0 {
+		return true
+	}
+	v[0], v[1] = x[0], x[1]
+	return false
+}
+
+func (v Uint128View) MarshalText() (out []byte, err error) {
+	if v[1] == 0 {
+		return strconv.AppendUint(out, v[0], 10), nil
+	}
+	return []byte(v.ToBig().String()), nil
+}
+
+func (v *Uint128View) UnmarshalText(b []byte) error {
+	x := new(big.Int)
+	err := x.UnmarshalText(b)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Uint128View: %w", err)
+	}
+	if v.SetFromBig(x) {
+		return strconv.ErrRange
+	}
+	return nil
+}
+
+func (v Uint128View) MarshalJSON() ([]byte, error) {
+	return conv.Uint128Marshal(v)
+}
+
+func (v *Uint128View) UnmarshalJSON(b []byte) error {
+	return conv.Uint128Unmarshal((*[2]uint64)(v), b)
+}
+
+func (v Uint128View) String() string {
+	if v[1] == 0 {
+		return strconv.FormatUint(v[0], 10)
+	}
+	return v.ToBig().String()
+}
+
+func MustUint128(v string) Uint128View {
+	var out Uint128View
+	err := out.UnmarshalText([]byte(v))
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
@@ -95,22 +95,29 @@ func (td *UnionTypeDef) New() *UnionView {
 func (td *UnionTypeDef) Deserialize(dr *codec.DecodingReader) (View, error) {
 	scope := dr.Scope()
 	if scope == 0 {
-		return nil, fmt.Errorf("scope must be non-zero to deserialize union")
+		return nil, dr.Errorf("scope must be non-zero to deserialize union")
 	}
 	selector, err := dr.ReadByte()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read selector: %v", err)
+		return nil, err
 	}
 	if selector >= uint8(len(td.Options)) {
-		return nil, fmt.Errorf("type selector is too large: %d (%d options)", selector, len(td.Options))
+		return nil, dr.Errorf("type selector is too large: %d (%d options)", selector, len(td.Options))
 	}
 	option := td.Options[selector]
 	if option == nil {
+		if scope != 1 {
+			return nil, dr.Errorf("union None option must not have a value, got %d bytes", scope-1)
+		}
 		return td.FromView(selector, nil)
 	}
+	start := dr.Position()
 	subView, err := option.Deserialize(dr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode union element (selector %d, scope %d): %v", selector, scope, err)
+		return nil, dr.WrapIndex(uint64(selector), err)
+	}
+	if err := dr.CheckConsumed(start, scope-1); err != nil {
+		return nil, err
 	}
 	return td.FromView(selector, subView)
 }
@@ -122,9 +129,15 @@ func (td *UnionTypeDef) String() string {
 func (td *UnionTypeDef) TypeRepr() string {
 	var buf bytes.Buffer
 	buf.WriteString("Union[")
-	for _, f := range td.Options {
-		buf.WriteString(f.String())
-		buf.WriteString(", ")
+	for i, f := range td.Options {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if f == nil {
+			buf.WriteString("None")
+		} else {
+			buf.WriteString(f.String())
+		}
 	}
 	buf.WriteRune(']')
 	return buf.String()
@@ -0,0 +1,393 @@
+package view
+
+import (
+	"fmt"
+	"github.com/protolambda/ztyp/bitfields"
+	"github.com/protolambda/ztyp/codec"
+	. "github.com/protolambda/ztyp/tree"
+)
+
+// ValidateBytes checks that data is the canonical SSZ encoding of a value of the given type.
+// See Validate.
+func ValidateBytes(typ TypeDef, data []byte) error {
+	return Validate(typ, codec.NewBytesDecodingReader(data))
+}
+
+// Validate checks that the full scope of the reader is the canonical SSZ encoding of a value of the given type,
+// without constructing a view of it. It rejects:
+//   - offsets that are not monotone, out of scope, or where the first offset does not match the fixed part size
+//   - bitlists without delimiter bit, and bitvectors with non-zero padding bits
+//   - union selectors that are out of range, and payloads for a None (nil) option
+//   - optional values without the 0x01 prefix
+//   - active fields of stable containers beyond their fields, and non-zero padding bits of active and optional fields
+//   - booleans other than 0 or 1
+//   - trailing bytes, in the outer scope or in the scope of any element
+//
+// Errors are of type *codec.DecodeError, with the byte position and path of the invalid value.
+// Types unknown to the validator are checked by deserializing them.
+func Validate(typ TypeDef, dr *codec.DecodingReader) error {
+	return validateScope(typ, dr)
+}
+
+func validateScope(typ TypeDef, dr *codec.DecodingReader) error {
+	scope := dr.Scope()
+	if typ.IsFixedByteLength() {
+		if size := typ.TypeByteLength(); scope != size {
+			return dr.Errorf("%s: expected %d bytes, got %d", typ.String(), size, scope)
+		}
+	} else if min, max := typ.MinByteLength(), typ.MaxByteLength(); scope < min || scope > max {
+		return dr.Errorf("%s: scope of %d bytes is out of range, expected %d to %d bytes", typ.String(), scope, min, max)
+	}
+	if err := validateContents(typ, dr); err != nil {
+		return err
+	}
+	if rem := dr.Scope(); rem != 0 {
+		return dr.Errorf("%s: %d trailing bytes", typ.String(), rem)
+	}
+	return nil
+}
+
+func validateContents(typ TypeDef, dr *codec.DecodingReader) error {
+	scope := dr.Scope()
+	switch t := typ.(type) {
+	case BoolMeta:
+		return validateBools(dr, scope)
+	case UintMeta, RootMeta, SmallByteVecMeta:
+		_, err := dr.Skip(scope)
+		return err
+	case *BasicVectorTypeDef:
+		// all byte values are valid for the supported basic element types
+		_, err := dr.Skip(scope)
+		return err
+	case *ByteVectorTypeDef, *ByteListTypeDef:
+		_, err := dr.Skip(scope)
+		return err
+	case *BasicListTypeDef:
+		if elemSize := t.ElemType.TypeByteLength(); scope%elemSize != 0 {
+			return dr.Errorf("%s: scope %d does not align to element size %d", t.String(), scope, elemSize)
+		}
+		_, err := dr.Skip(scope)
+		return err
+	case *BasicProgressiveListTypeDef:
+		if elemSize := t.ElemType.TypeByteLength(); scope%elemSize != 0 {
+			return dr.Errorf("%s: scope %d does not align to element size %d", t.String(), scope, elemSize)
+		}
+		_, err := dr.Skip(scope)
+		return err
+	case *BitVectorTypeDef:
+		contents, err := dr.ReadBytes(scope)
+		if err != nil {
+			return err
+		}
+		if err := bitfields.BitvectorCheck(contents, t.BitLength); err != nil {
+			return &codec.DecodeError{Offset: dr.Position() - 1, Err: err}
+		}
+		return nil
+	case *BitListTypeDef:
+		contents, err := dr.ReadBytes(scope)
+		if err != nil {
+			return err
+		}
+		if err := bitfields.BitlistCheck(contents, t.BitLimit); err != nil {
+			return &codec.DecodeError{Offset: dr.Position() - 1, Err: err}
+		}
+		return nil
+	case *ComplexVectorTypeDef:
+		return validateComplexSeries(t.ElemType, dr, scope, t.VectorLength, true)
+	case *ComplexListTypeDef:
+		return validateComplexSeries(t.ElemType, dr, scope, t.ListLimit, false)
+	case *ComplexProgressiveListTypeDef:
+		return validateComplexSeries(t.ElemType, dr, scope, ProgressiveMaxChunks, false)
+	case *ContainerTypeDef:
+		return validateFields(t.Fields, t.FixedPartSize, dr, scope)
+	case *UnionTypeDef:
+		return validateUnion(t, dr)
+	case *OptionalTypeDef:
+		return validateOptional(t, dr)
+	case *StableContainerTypeDef:
+		return validateStableContainer(t, dr)
+	case *ProfileTypeDef:
+		return validateProfile(t, dr)
+	default:
+		_, err := typ.Deserialize(dr)
+		return err
+	}
+}
+
+func validateBools(dr *codec.DecodingReader, count uint64) error {
+	for i := uint64(0); i < count; i++ {
+		b, err := dr.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b > 1 {
+			return &codec.DecodeError{Offset: dr.Position() - 1, Err: fmt.Errorf("invalid bool value: 0x%x", b)}
+		}
+	}
+	return nil
+}
+
+// validateSub validates the next count bytes as a value of the given type,
+// and moves the parent scope forward past them.
+func validateSub(typ TypeDef, dr *codec.DecodingReader, count uint64) error {
+	sub, err := dr.SubScope(count)
+	if err != nil {
+		return err
+	}
+	if err := validateScope(typ, sub); err != nil {
+		return err
+	}
+	dr.UpdateIndexFromScoped(sub)
+	return nil
+}
+
+// readOffsets reads offsets until there are count offsets, and checks they are monotone and within scope.
+// If there are no offsets yet, the first offset must be equal to the given fixed part size.
+func readOffsets(dr *codec.DecodingReader, offsets []uint64, count uint64, scope uint64, fixedPart uint64) ([]uint64, error) {
+	for i := uint64(len(offsets)); i < count; i++ {
+		pos := dr.Position()
+		off, err := dr.ReadOffset()
+		if err != nil {
+			return nil, err
+		}
+		offset := uint64(off)
+		if i == 0 && offset != fixedPart {
+			return nil, &codec.DecodeError{Offset: pos, Err: fmt.Errorf("first offset %d does not match fixed part size %d", offset, fixedPart)}
+		}
+		if i > 0 && offset < offsets[i-1] {
+			return nil, &codec.DecodeError{Offset: pos, Err: fmt.Errorf("offset %d is smaller than previous offset %d", offset, offsets[i-1])}
+		}
+		if offset > scope {
+			return nil, &codec.DecodeError{Offset: pos, Err: fmt.Errorf("offset %d is out of scope %d", offset, scope)}
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+func validateComplexSeries(elemType TypeDef, dr *codec.DecodingReader, scope uint64, lengthOrLimit uint64, isVector bool) error {
+	if elemType.IsFixedByteLength() {
+		elemSize := elemType.TypeByteLength()
+		if scope%elemSize != 0 {
+			return dr.Errorf("scope %d does not align to element size %d", scope, elemSize)
+		}
+		length := scope / elemSize
+		if length > lengthOrLimit || (isVector && length != lengthOrLimit) {
+			return dr.Errorf("got %d elements, expected %d", length, lengthOrLimit)
+		}
+		for i := uint64(0); i < length; i++ {
+			if err := validateSub(elemType, dr, elemSize); err != nil {
+				return dr.WrapIndex(i, err)
+			}
+		}
+		return nil
+	}
+	if isVector {
+		offsets, err := readOffsets(dr, nil, lengthOrLimit, scope, lengthOrLimit*OffsetByteLength)
+		if err != nil {
+			return err
+		}
+		return validateDynElements(elemType, dr, scope, offsets, nil)
+	}
+	if scope == 0 {
+		return nil
+	}
+	// the first offset determines the length of the list
+	pos := dr.Position()
+	firstOffset, err := dr.ReadOffset()
+	if err != nil {
+		return err
+	}
+	first := uint64(firstOffset)
+	if first == 0 || first%OffsetByteLength != 0 {
+		return &codec.DecodeError{Offset: pos, Err: fmt.Errorf("first offset %d is not a non-zero multiple of %d", first, OffsetByteLength)}
+	}
+	if first > scope {
+		return &codec.DecodeError{Offset: pos, Err: fmt.Errorf("first offset %d is out of scope %d", first, scope)}
+	}
+	length := first / OffsetByteLength
+	if length > lengthOrLimit {
+		return &codec.DecodeError{Offset: pos, Err: fmt.Errorf("too many elements: %d, limit is %d", length, lengthOrLimit)}
+	}
+	offsets := make([]uint64, 1, length)
+	offsets[0] = first
+	offsets, err = readOffsets(dr, offsets, length, scope, first)
+	if err != nil {
+		return err
+	}
+	return validateDynElements(elemType, dr, scope, offsets, nil)
+}
+
+// validateDynElements validates the dynamic part of a series or container, given the offsets that were read.
+// If fields is not nil, errors are attributed to the field names by index, instead of element indices.
+func validateDynElements(elemType TypeDef, dr *codec.DecodingReader, scope uint64, offsets []uint64, fields []FieldDef) error {
+	for i, off := range offsets {
+		next := scope
+		if i+1 < len(offsets) {
+			next = offsets[i+1]
+		}
+		if fields != nil {
+			if err := validateSub(fields[i].Type, dr, next-off); err != nil {
+				return dr.WrapField(fields[i].Name, err)
+			}
+		} else {
+			if err := validateSub(elemType, dr, next-off); err != nil {
+				return dr.WrapIndex(uint64(i), err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateFields validates the fields like a container, with the given size of the fixed part of the fields.
+func validateFields(fields []FieldDef, fixedPartSize uint64, dr *codec.DecodingReader, scope uint64) error {
+	var offsets []uint64
+	var dynFields []FieldDef
+	prev := fixedPartSize
+	for _, f := range fields {
+		if f.Type.IsFixedByteLength() {
+			if err := validateSub(f.Type, dr, f.Type.TypeByteLength()); err != nil {
+				return dr.WrapField(f.Name, err)
+			}
+			continue
+		}
+		pos := dr.Position()
+		off, err := dr.ReadOffset()
+		if err != nil {
+			return dr.WrapField(f.Name, err)
+		}
+		offset := uint64(off)
+		if len(offsets) == 0 && offset != fixedPartSize {
+			return wrapFieldAt(f.Name, pos, fmt.Errorf("first offset %d does not match fixed part size %d", offset, fixedPartSize))
+		}
+		if offset < prev {
+			return wrapFieldAt(f.Name, pos, fmt.Errorf("offset %d is smaller than previous offset %d", offset, prev))
+		}
+		if offset > scope {
+			return wrapFieldAt(f.Name, pos, fmt.Errorf("offset %d is out of scope %d", offset, scope))
+		}
+		prev = offset
+		offsets = append(offsets, offset)
+		dynFields = append(dynFields, f)
+	}
+	return validateDynElements(nil, dr, scope, offsets, dynFields)
+}
+
+func wrapFieldAt(name string, pos uint64, err error) error {
+	return &codec.DecodeError{Offset: pos, Path: []codec.PathElem{{Field: name}}, Err: err}
+}
+
+func validateUnion(td *UnionTypeDef, dr *codec.DecodingReader) error {
+	pos := dr.Position()
+	selector, err := dr.ReadByte()
+	if err != nil {
+		return err
+	}
+	if uint64(selector) >= uint64(len(td.Options)) {
+		return &codec.DecodeError{Offset: pos, Err: fmt.Errorf("union selector %d is out of range (%d options)", selector, len(td.Options))}
+	}
+	option := td.Options[selector]
+	if option == nil {
+		if rem := dr.Scope(); rem != 0 {
+			return dr.Errorf("union None option (selector %d) must not have a payload, got %d bytes", selector, rem)
+		}
+		return nil
+	}
+	if err := validateSub(option, dr, dr.Scope()); err != nil {
+		return dr.WrapIndex(uint64(selector), err)
+	}
+	return nil
+}
+
+func validateOptional(td *OptionalTypeDef, dr *codec.DecodingReader) error {
+	if dr.Scope() == 0 {
+		// None
+		return nil
+	}
+	pos := dr.Position()
+	prefix, err := dr.ReadByte()
+	if err != nil {
+		return err
+	}
+	if prefix != 1 {
+		return &codec.DecodeError{Offset: pos, Err: fmt.Errorf("optional value must be prefixed with 0x01, got 0x%x", prefix)}
+	}
+	if err := validateSub(td.ElemType, dr, dr.Scope()); err != nil {
+		return dr.WrapIndex(0, err)
+	}
+	return nil
+}
+
+// validateActiveBits reads and checks a bitvector, and returns the bits.
+// Bits at or beyond the given count must be zero.
+func validateActiveBits(td *BitVectorTypeDef, count uint64, dr *codec.DecodingReader) ([]bool, error) {
+	pos := dr.Position()
+	contents, err := dr.ReadBytes(td.TypeByteLength())
+	if err != nil {
+		return nil, err
+	}
+	if err := bitfields.BitvectorCheck(contents, td.BitLength); err != nil {
+		return nil, &codec.DecodeError{Offset: dr.Position() - 1, Err: err}
+	}
+	bits := make([]bool, count, count)
+	for i := uint64(0); i < td.BitLength; i++ {
+		if !bitfields.GetBit(contents, i) {
+			continue
+		}
+		if i >= count {
+			return nil, &codec.DecodeError{Offset: pos + (i >> 3), Err: fmt.Errorf("bit %d is set, but there are only %d fields", i, count)}
+		}
+		bits[i] = true
+	}
+	return bits, nil
+}
+
+// validatePresentFields validates the present fields like a container.
+func validatePresentFields(fields []FieldDef, present []bool, dr *codec.DecodingReader) error {
+	var presentFields []FieldDef
+	fixedPartSize := uint64(0)
+	for i, f := range fields {
+		if !present[i] {
+			continue
+		}
+		presentFields = append(presentFields, f)
+		if f.Type.IsFixedByteLength() {
+			fixedPartSize += f.Type.TypeByteLength()
+		} else {
+			fixedPartSize += OffsetByteLength
+		}
+	}
+	return validateFields(presentFields, fixedPartSize, dr, dr.Scope())
+}
+
+func validateStableContainer(td *StableContainerTypeDef, dr *codec.DecodingReader) error {
+	active, err := validateActiveBits(td.ActiveFieldsType, uint64(len(td.Fields)), dr)
+	if err != nil {
+		return err
+	}
+	return validatePresentFields(td.Fields, active, dr)
+}
+
+func validateProfile(td *ProfileTypeDef, dr *codec.DecodingReader) error {
+	var optionalActive []bool
+	if td.OptionalFieldsType != nil {
+		var err error
+		optionalActive, err = validateActiveBits(td.OptionalFieldsType, td.OptionalFieldsType.BitLength, dr)
+		if err != nil {
+			return err
+		}
+	}
+	fields := make([]FieldDef, len(td.Fields), len(td.Fields))
+	present := make([]bool, len(td.Fields), len(td.Fields))
+	j := 0
+	for i, f := range td.Fields {
+		fields[i] = FieldDef{Name: f.Name, Type: f.Type}
+		if f.Optional {
+			present[i] = optionalActive[j]
+			j++
+		} else {
+			present[i] = true
+		}
+	}
+	return validatePresentFields(fields, present, dr)
+}
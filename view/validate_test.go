@@ -0,0 +1,114 @@
+package view
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/protolambda/ztyp/codec"
+	"testing"
+)
+
+func TestValidateCanonical(t *testing.T) {
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateBytes(tt.value.Type(), data); err != nil {
+				t.Fatalf("expected canonical encoding to be valid: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateNonCanonical(t *testing.T) {
+	optionalType := UnionType([]TypeDef{nil, Uint16Type})
+	cases := []struct {
+		name   string
+		typ    TypeDef
+		hex    string
+		offset uint64
+		path   string
+	}{
+		{"bool 2", BoolType, "02", 0, ""},
+		{"trailing byte", Uint16Type, "000000", 0, ""},
+		{"bitlist no delimiter", BitListType(8), "2b00", 1, ""},
+		{"bitvector padding", BitVectorType(3), "0a", 0, ""},
+		{"first offset too large", VarTestStructType, "cdab08000000ff00", 2, "B"},
+		{"list offsets not monotone", ListBType, "0800000007000000", 4, ""},
+		{"list first offset out of scope", ListBType, "08000000", 0, ""},
+		{"nested element misaligned", ListBType,
+			"08000000" + "15000000" + "adde0700000011010002000300" + "efbe070000002204000500060000", 28, "[1].B"},
+		{"union selector out of range", optionalType, "02", 0, ""},
+		{"union none with payload", optionalType, "000000", 1, ""},
+		{"optional prefix", OptionalType(Uint16Type), "020000", 0, ""},
+		{"optional invalid value", OptionalType(BoolType), "0102", 1, "[0]"},
+		{"optional misaligned list", OptionalType(BasicListType(Uint16Type, 4)), "01" + "000000", 1, "[0]"},
+		{"stable container field beyond fields", ShapeType, "08", 0, ""},
+		{"stable container active padding", ShapeType, "10", 0, ""},
+		{"stable container trailing byte", ShapeType, "01" + "4200" + "00", 3, ""},
+		{"stable container invalid field", StableContainerType("Flags", 2, []FieldDef{{"a", BoolType}}), "01" + "02", 1, "a"},
+		{"profile optional padding", CircleType, "02" + "01", 0, ""},
+		{"profile missing optional field", CircleType, "01" + "01", 2, "radius"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ValidateBytes(tt.typ, data)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			var decErr *codec.DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("expected decode error, got %v", err)
+			}
+			if decErr.Offset != tt.offset {
+				t.Errorf("expected error at byte %d, got %d: %v", tt.offset, decErr.Offset, err)
+			}
+			if p := decErr.PathString(); p != tt.path {
+				t.Errorf("expected path %q, got %q: %v", tt.path, p, err)
+			}
+		})
+	}
+}
+
+func TestDeserializeNonCanonical(t *testing.T) {
+	// the decoders reject these non-canonical encodings too, not only the validator
+	elem := "0000" + "07000000" + "00"
+	cases := []struct {
+		name string
+		typ  TypeDef
+		hex  string
+	}{
+		{"union none with payload", UnionType([]TypeDef{nil, Uint16Type}), "000000"},
+		{"union trailing byte", UnionType([]TypeDef{nil, Uint16Type}), "01" + "0100" + "00"},
+		{"container first offset", VarTestStructType, "cdab08000000ff00"},
+		{"vector first offset", VectorType(VarTestStructType, 2), "0c000000" + "13000000" + "00000000" + elem + elem},
+		{"list first offset out of scope", ListBType, "08000000"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.typ.Deserialize(codec.NewBytesDecodingReader(data)); err == nil {
+				t.Fatal("expected decoding error")
+			}
+			if err := ValidateBytes(tt.typ, data); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+	// the canonical vector encoding is accepted
+	data, err := hex.DecodeString("08000000" + "0f000000" + elem + elem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VectorType(VarTestStructType, 2).Deserialize(codec.NewBytesDecodingReader(data)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/protolambda/ztyp/codec"
 	"github.com/protolambda/ztyp/tree"
@@ -136,6 +137,14 @@ func init() {
 		return v
 	}
 
+	seq := func(n int) []byte {
+		out := make([]byte, n, n)
+		for i := range out {
+			out[i] = byte(i)
+		}
+		return out
+	}
+
 	viewMust := func(v View, err error) View {
 		if err != nil {
 			panic(err)
@@ -179,8 +188,25 @@ func init() {
 		{"uint32 01234567", Uint32View(0x01234567), "67452301", chunk("67452301")},
 		{"uint64 0000000000000000", Uint64View(0), "0000000000000000", chunk("0000000000000000")},
 		{"uint64 0123456789abcdef", Uint64View(0x0123456789abcdef), "efcdab8967452301", chunk("efcdab8967452301")},
+		{"uint128 00000000000000000000000000000000", Uint128View{}, "00000000000000000000000000000000", chunk("")},
+		{"uint128 0f0e0d0c0b0a09080706050403020100", MustUint128("0x0f0e0d0c0b0a09080706050403020100"), "000102030405060708090a0b0c0d0e0f", chunk("000102030405060708090a0b0c0d0e0f")},
 		{"uint256 0000000000000000000000000000000000000000000000000000000000000000", Uint256View{}, "0000000000000000000000000000000000000000000000000000000000000000", "0000000000000000000000000000000000000000000000000000000000000000"},
 		{"uint256 f1f0e1e0d1d0c1c0b1b0a1a09190818071706160515041403130212011100100", MustUint256("0xf1f0e1e0d1d0c1c0b1b0a1a09190818071706160515041403130212011100100"), "0001101120213031404150516061707180819091a0a1b0b1c0c1d0d1e0e1f0f1", "0001101120213031404150516061707180819091a0a1b0b1c0c1d0d1e0e1f0f1"},
+		{"raw bytes48", viewMust(Bytes48Type.FromBytes(seq(48))), "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f",
+			h("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "202122232425262728292a2b2c2d2e2f00000000000000000000000000000000"),
+		},
+		{"raw small empty bytelist", ByteListType(10).New(), "", h(chunk(""), chunk("00"))},
+		{"raw big empty bytelist", ByteListType(2048).New(), "", h(zeroHashes[6], chunk("00"))},
+		{"raw bytelist 7", viewMust(ByteListType(7).FromBytes(seq(7))), "00010203040506", h(chunk("00010203040506"), chunk("07"))},
+		{"raw bytelist 50", viewMust(ByteListType(50).FromBytes(seq(50))), "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f3031",
+			h(h("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "202122232425262728292a2b2c2d2e2f30310000000000000000000000000000"), chunk("32")),
+		},
+		{"raw bytelist 6/256", viewMust(ByteListType(256).FromBytes(seq(6))), "000102030405",
+			h(h(h(h(chunk("000102030405"), zeroHashes[0]), zeroHashes[1]), zeroHashes[2]), chunk("06")),
+		},
+		{"raw sig", viewMust(Bytes96Type.FromBytes(sigBytes[:])), "01" + repeat("00", 31) + "02" + repeat("00", 31) + "03" + repeat("00", 30) + "ff",
+			h(h(chunk("01"), chunk("02")), h("03"+repeat("00", 30)+"ff", chunk(""))),
+		},
 		// TODO: bytelist type/view that is not backed by a tree, but makes the tree on demand, possible optimization.
 		//	("bytes48", Vector[byte, 48], Vector[byte, 48](*range(48)), "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f",
 		// h("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "202122232425262728292a2b2c2d2e2f00000000000000000000000000000000")),
@@ -234,6 +260,42 @@ func init() {
 			// max length: 128 * 4 = 512 bytes = 16 chunks
 			h(merge(chunk("bbaa0000adc00000ffee0000"), zeroHashes[0:4]), chunk("03000000")),
 		},
+		{"uint128 list", viewMust(BasicListType(Uint128Type, 8).FromElements(Uint128View{0xaabb}, Uint128View{0xc0ad}, Uint128View{0xeeff, 1})),
+			"bbaa0000000000000000000000000000adc00000000000000000000000000000ffee0000000000000100000000000000",
+			// two per chunk, max length: 8 * 16 = 128 bytes = 4 chunks
+			h(merge(h("bbaa0000000000000000000000000000adc00000000000000000000000000000", chunk("ffee0000000000000100000000000000")), zeroHashes[1:2]), chunk("03000000")),
+		},
+		{"empty progressive list", BasicProgressiveListType(Uint64Type).New(), "", h(chunk(""), chunk("00"))},
+		{"uint64 progressive list", viewMust(BasicProgressiveListType(Uint64Type).FromElements(Uint64View(1), Uint64View(2), Uint64View(3), Uint64View(4), Uint64View(5))),
+			"0100000000000000020000000000000003000000000000000400000000000000" + "0500000000000000",
+			// stage 0: 1 chunk, stage 1: 4 chunks
+			h(h(h(chunk(""), h(h(chunk("05"), chunk("")), zeroHashes[1])), "0100000000000000020000000000000003000000000000000400000000000000"), chunk("05")),
+		},
+		{"bytes32 progressive list", viewMust(ComplexProgressiveListType(RootType).FromElements(&RootView{0xbb, 0xaa}, &RootView{0xad, 0xc0})),
+			"bbaa000000000000000000000000000000000000000000000000000000000000" +
+				"adc0000000000000000000000000000000000000000000000000000000000000",
+			h(h(h(chunk(""), h(h(chunk("adc0"), chunk("")), zeroHashes[1])), chunk("bbaa")), chunk("02")),
+		},
+		{"optional none", OptionalType(Uint64Type).None(), "", h(chunk(""), chunk(""))},
+		{"optional uint64", viewMust(OptionalType(Uint64Type).Some(Uint64View(0x42))), "01" + "4200000000000000", h(chunk("42"), chunk("01"))},
+		{"optional uint32 list", viewMust(OptionalType(BasicListType(Uint32Type, 8)).Some(viewMust(BasicListType(Uint32Type, 8).FromElements(Uint32View(0xaabb))))),
+			"01" + "bbaa0000",
+			// max length: 8 * 4 = 32 bytes = 1 chunk
+			h(h(chunk("bbaa0000"), chunk("01")), chunk("01")),
+		},
+		{"empty stable container", ShapeType.New(), "00", h(zeroHashes[2], chunk("00"))},
+		{"stable container", viewMust(ShapeType.FromFields(Uint16View(0x42), Uint8View(1), nil)), "03" + "420001",
+			h(h(h(chunk("4200"), chunk("01")), zeroHashes[1]), chunk("03")),
+		},
+		{"profile", viewMust(SquareType.FromFields(Uint16View(0x42), Uint8View(1))), "420001",
+			h(h(h(chunk("4200"), chunk("01")), zeroHashes[1]), chunk("03")),
+		},
+		{"profile with optional field", viewMust(CircleType.FromFields(Uint8View(1), Uint16View(0x42))), "01" + "01" + "4200",
+			h(h(h(chunk(""), chunk("01")), h(chunk("4200"), chunk(""))), chunk("06")),
+		},
+		{"profile without optional field", viewMust(CircleType.FromFields(Uint8View(1), nil)), "00" + "01",
+			h(h(h(chunk(""), chunk("01")), zeroHashes[1]), chunk("02")),
+		},
 		{"bytes32 list", viewMust(ComplexListType(RootType, 64).FromElements(&RootView{0xbb, 0xaa}, &RootView{0xad, 0xc0}, &RootView{0xff, 0xee})),
 			"bbaa000000000000000000000000000000000000000000000000000000000000" +
 				"adc0000000000000000000000000000000000000000000000000000000000000" +
@@ -453,6 +515,32 @@ func TestSerializeView(t *testing.T) {
 	}
 }
 
+func TestSerializeToBytes(t *testing.T) {
+	var pool codec.SyncBufferPool
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := SerializeToBytes(tt.value)
+			if err != nil {
+				t.Fatalf("encoding failed, err: %v", err)
+			}
+			if res := fmt.Sprintf("%x", data); res != tt.hex {
+				t.Fatalf("encoded different data:\n     got %s\nexpected %s", res, tt.hex)
+			}
+			if len(data) != cap(data) {
+				t.Errorf("expected exact allocation, got %d bytes with capacity %d", len(data), cap(data))
+			}
+			pooled, err := SerializeToPool(tt.value, &pool)
+			if err != nil {
+				t.Fatalf("encoding to pooled buffer failed, err: %v", err)
+			}
+			if res := fmt.Sprintf("%x", pooled); res != tt.hex {
+				t.Fatalf("encoded different data to pooled buffer:\n     got %s\nexpected %s", res, tt.hex)
+			}
+			pool.Put(pooled)
+		})
+	}
+}
+
 func TestDeserializeSerialize(t *testing.T) {
 	for _, tt := range testCases {
 		v := reflect.New(reflect.TypeOf(tt.value))
@@ -563,6 +651,26 @@ func TestDeserializeView(t *testing.T) {
 	}
 }
 
+func TestDeserializeViewFromBytes(t *testing.T) {
+	hFn := tree.GetHashFn()
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dest, err := tt.value.Type().Deserialize(codec.NewBytesDecodingReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := dest.HashTreeRoot(hFn)
+			if hexRoot := hex.EncodeToString(root[:]); hexRoot != tt.root {
+				t.Errorf("Hash tree root of deserialized object does not match expected root. Got: %s, expected: %s.", hexRoot, tt.root)
+			}
+		})
+	}
+}
+
 func TestHashTreeRoot(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -611,3 +719,35 @@ func TestTypeBounds(t *testing.T) {
 		})
 	}
 }
+
+func TestDeserializeErrorPath(t *testing.T) {
+	// second element of the list has a B field with a misaligned uint16 list
+	data, err := hex.DecodeString("08000000" + "15000000" +
+		"adde0700000011010002000300" +
+		"efbe070000002204000500060000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers := map[string]*codec.DecodingReader{
+		"reader": codec.NewDecodingReader(bytes.NewReader(data), uint64(len(data))),
+		"bytes":  codec.NewBytesDecodingReader(data),
+	}
+	for name, dr := range readers {
+		t.Run(name, func(t *testing.T) {
+			_, err := ListBType.Deserialize(dr)
+			if err == nil {
+				t.Fatal("expected decoding error")
+			}
+			var decErr *codec.DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("expected decode error, got %v", err)
+			}
+			if decErr.Offset != 28 {
+				t.Errorf("expected error at byte 28, got %d: %v", decErr.Offset, err)
+			}
+			if p := decErr.PathString(); p != "[1].B" {
+				t.Errorf("unexpected error path %q: %v", p, err)
+			}
+		})
+	}
+}